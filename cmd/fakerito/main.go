@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/fakerito"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8089", "address to listen on")
+	fixtures := flag.String("fixtures", "internal/infrastructure/providers/jsons", "fixtures directory")
+	scenarios := flag.String("scenarios", "", "json file with the scripted scenarios")
+	key := flag.String("key", "", "rito token to accept, any token is accepted when empty")
+	flag.Parse()
+
+	var options []fakerito.Option
+	if len(*key) > 0 {
+		options = append(options, fakerito.WithKeys(*key))
+	}
+	if len(*scenarios) > 0 {
+		loaded, err := fakerito.LoadScenarios(*scenarios)
+		if err != nil {
+			log.Fatalf("Something went wrong trying to load the scenarios. %s", err)
+		}
+		options = append(options, fakerito.WithScenarios(loaded...))
+	}
+
+	log.Printf("fake rito api listening on %s serving %s\n", *addr, *fixtures)
+	log.Fatal(http.ListenAndServe(*addr, fakerito.New(*fixtures, options...)))
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"time"
+)
+
+type APIClientService interface {
+	// Issue creates a client with a new key, the default requests per minute are used when not positive
+	Issue(name string, requestsPerMinute int) (*domain.APIClient, error)
+	// Revoke returns ErrAPIClientNotFound when there is no client with the id
+	Revoke(id string) error
+	// FindClients returns the clients without their keys
+	FindClients() ([]domain.APIClient, error)
+	// Authenticate returns ErrAPIClientNotFound when the key was never issued or was revoked.
+	// Clients written by hand without requests per minute get the default ones.
+	Authenticate(key string) (*domain.APIClient, error)
+}
+
+type apiClientService struct {
+	clients                  APIClientRepository
+	defaultRequestsPerMinute int
+}
+
+func (s apiClientService) Issue(name string, requestsPerMinute int) (*domain.APIClient, error) {
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = s.defaultRequestsPerMinute
+	}
+	id, err := randomHex(4)
+	if err != nil {
+		return nil, err
+	}
+	key, err := randomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	client := domain.APIClient{
+		Id:                id,
+		Name:              name,
+		Key:               key,
+		RequestsPerMinute: requestsPerMinute,
+		CreatedAt:         time.Now().UTC(),
+	}
+	if err = s.clients.SaveAPIClient(client); err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (s apiClientService) Revoke(id string) error {
+	return s.clients.DeleteAPIClient(id)
+}
+
+func (s apiClientService) FindClients() ([]domain.APIClient, error) {
+	clients, err := s.clients.FindAPIClients()
+	if err != nil {
+		return nil, err
+	}
+	listed := make([]domain.APIClient, 0, len(clients))
+	for _, client := range clients {
+		listed = append(listed, client.WithoutKey())
+	}
+	return listed, nil
+}
+
+func (s apiClientService) Authenticate(key string) (*domain.APIClient, error) {
+	if key == "" {
+		return nil, ErrAPIClientNotFound
+	}
+	client, err := s.clients.FindAPIClientByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if client.RequestsPerMinute <= 0 {
+		client.RequestsPerMinute = s.defaultRequestsPerMinute
+	}
+	return client, nil
+}
+
+func randomHex(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func NewAPIClientService(clients APIClientRepository, defaultRequestsPerMinute int) APIClientService {
+	if defaultRequestsPerMinute <= 0 {
+		defaultRequestsPerMinute = 1
+	}
+	return apiClientService{clients: clients, defaultRequestsPerMinute: defaultRequestsPerMinute}
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"sync"
+	"time"
+)
+
+// topChampions is the number of champions shown for each scouted player
+const topChampions = 3
+
+type ClashService interface {
+	// ScoutTeam returns the clash team the summoner is registered in, with the ranks and top champions of every player
+	ScoutTeam(ctx context.Context, region string, summonerName string) (*domain.ClashTeam, error)
+	FindTournaments(ctx context.Context, region string) ([]domain.ClashTournament, error)
+}
+
+type clashService struct {
+	ritoProvider RitoProvider
+}
+
+func (c clashService) ScoutTeam(ctx context.Context, region string, summonerName string) (*domain.ClashTeam, error) {
+	summonerDTO, err := c.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, err
+	}
+	playersDTO, err := c.ritoProvider.FindClashPlayersByRegionAndSummonerId(ctx, region, summonerDTO.Id)
+	if err != nil {
+		return nil, err
+	}
+	var teamId string
+	for _, playerDTO := range playersDTO {
+		if playerDTO.TeamId != "" {
+			teamId = playerDTO.TeamId
+			break
+		}
+	}
+	if teamId == "" {
+		return nil, ErrClashTeamNotFound
+	}
+
+	teamDTO, err := c.ritoProvider.FindClashTeamByRegionAndId(ctx, region, teamId)
+	if err != nil {
+		return nil, err
+	}
+	team := &domain.ClashTeam{
+		Id:           teamDTO.Id,
+		TournamentId: teamDTO.TournamentId,
+		Name:         teamDTO.Name,
+		Abbreviation: teamDTO.Abbreviation,
+		Tier:         teamDTO.Tier,
+		Players:      make([]domain.ClashPlayer, len(teamDTO.Players)),
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(teamDTO.Players))
+	for i, playerDTO := range teamDTO.Players {
+		go func(i int, playerDTO providers.ClashPlayerDTO) {
+			defer wg.Done()
+			team.Players[i] = c.scoutPlayer(ctx, region, playerDTO)
+		}(i, playerDTO)
+	}
+	wg.Wait()
+	domain.SortClashPlayers(team.Players)
+
+	return team, nil
+}
+
+func (c clashService) FindTournaments(ctx context.Context, region string) ([]domain.ClashTournament, error) {
+	tournamentsDTO, err := c.ritoProvider.FindClashTournamentsByRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	tournaments := make([]domain.ClashTournament, 0, len(tournamentsDTO))
+	for _, tournamentDTO := range tournamentsDTO {
+		tournament := domain.ClashTournament{
+			Id:            tournamentDTO.Id,
+			Name:          tournamentDTO.NameKey,
+			SecondaryName: tournamentDTO.NameKeySecondary,
+			Schedule:      make([]domain.ClashPhase, 0, len(tournamentDTO.Schedule)),
+		}
+		for _, phaseDTO := range tournamentDTO.Schedule {
+			tournament.Schedule = append(tournament.Schedule, domain.ClashPhase{
+				Id:               phaseDTO.Id,
+				RegistrationTime: fromEpochMillis(phaseDTO.RegistrationTime),
+				StartTime:        fromEpochMillis(phaseDTO.StartTime),
+				Cancelled:        phaseDTO.Cancelled,
+			})
+		}
+		tournaments = append(tournaments, tournament)
+	}
+	return tournaments, nil
+}
+
+// scoutPlayer enriches the player with its leagues and top champions, a failure leaves them empty
+func (c clashService) scoutPlayer(ctx context.Context, region string, playerDTO providers.ClashPlayerDTO) domain.ClashPlayer {
+	player := domain.ClashPlayer{
+		SummonerId:   playerDTO.SummonerId,
+		Position:     playerDTO.Position,
+		Role:         playerDTO.Role,
+		Leagues:      []domain.League{},
+		TopChampions: []domain.ChampionMastery{},
+	}
+	summonerDTO, err := c.ritoProvider.FindSummonerByRegionAndId(ctx, region, playerDTO.SummonerId)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the clash player", "summoner_id", playerDTO.SummonerId, "err", err)
+		return player
+	}
+	player.SummonerName = summonerDTO.Name
+
+	leaguesDTO, err := c.ritoProvider.FindLeaguesByRegionAndSummonerId(ctx, region, playerDTO.SummonerId)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the leagues", "summoner_id", playerDTO.SummonerId, "err", err)
+	}
+	player.Leagues = buildLeagues(leaguesDTO)
+
+	masteriesDTO, err := c.ritoProvider.FindChampionMasteriesByRegionAndSummonerId(ctx, region, playerDTO.SummonerId)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the champion masteries", "summoner_id", playerDTO.SummonerId, "err", err)
+	}
+	for _, masteryDTO := range masteriesDTO {
+		if len(player.TopChampions) == topChampions {
+			break
+		}
+		player.TopChampions = append(player.TopChampions, domain.ChampionMastery{
+			ChampionId: masteryDTO.ChampionId,
+			Level:      masteryDTO.ChampionLevel,
+			Points:     masteryDTO.ChampionPoints,
+		})
+	}
+	return player
+}
+
+func fromEpochMillis(millis int64) time.Time {
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+func NewClashService(provider RitoProvider) ClashService {
+	return clashService{ritoProvider: provider}
+}
@@ -1,5 +1,37 @@
 package application
 
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	platformHostEnvPrefix = "RITO_PLATFORM_HOST_"
+	regionalHostEnvPrefix = "RITO_REGIONAL_HOST_"
+)
+
+// HostsConfig has the rito base urls. Platform hosts are keyed by region (euw1, na1...) and
+// regional hosts by routing value (americas, europe...).
+type HostsConfig struct {
+	Platforms map[string]string `json:"platforms"`
+	Regionals map[string]string `json:"regionals"`
+}
+
+// Regions returns the sorted regions with a platform host
+func (c HostsConfig) Regions() []string {
+	regions := make([]string, 0, len(c.Platforms))
+	for region := range c.Platforms {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
 func GetRitoHosts() map[string]string {
 	return map[string]string{
 		"euw1": "https://euw1.api.riotgames.com",
@@ -14,3 +46,84 @@ func GetRitoHosts() map[string]string {
 		"tr1":  "https://tr1.api.riotgames.com",
 	}
 }
+
+func GetRitoRegionalHosts() map[string]string {
+	return map[string]string{
+		"americas": "https://americas.api.riotgames.com",
+		"asia":     "https://asia.api.riotgames.com",
+		"europe":   "https://europe.api.riotgames.com",
+		"sea":      "https://sea.api.riotgames.com",
+	}
+}
+
+// LoadHostsConfig starts from the rito hosts and applies, in order:
+// the RITO_HOSTS_FILE json file, which replaces each section it defines,
+// RITO_BASE_URL, which points every host to the same url (useful for a local emulator),
+// and RITO_PLATFORM_HOST_<REGION> / RITO_REGIONAL_HOST_<ROUTING> for single hosts.
+func LoadHostsConfig() (HostsConfig, error) {
+	config := HostsConfig{
+		Platforms: GetRitoHosts(),
+		Regionals: GetRitoRegionalHosts(),
+	}
+
+	if path := os.Getenv("RITO_HOSTS_FILE"); len(path) > 0 {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return HostsConfig{}, fmt.Errorf("could not read hosts file: %s", err)
+		}
+		var fileConfig HostsConfig
+		if err = json.Unmarshal(content, &fileConfig); err != nil {
+			return HostsConfig{}, fmt.Errorf("invalid hosts file %s: %s", path, err)
+		}
+		if len(fileConfig.Platforms) > 0 {
+			config.Platforms = fileConfig.Platforms
+		}
+		if len(fileConfig.Regionals) > 0 {
+			config.Regionals = fileConfig.Regionals
+		}
+	}
+
+	if baseURL := os.Getenv("RITO_BASE_URL"); len(baseURL) > 0 {
+		for region := range config.Platforms {
+			config.Platforms[region] = baseURL
+		}
+		for routing := range config.Regionals {
+			config.Regionals[routing] = baseURL
+		}
+	}
+
+	for _, env := range os.Environ() {
+		name, value := splitEnv(env)
+		if strings.HasPrefix(name, platformHostEnvPrefix) {
+			config.Platforms[strings.ToLower(strings.TrimPrefix(name, platformHostEnvPrefix))] = value
+		}
+		if strings.HasPrefix(name, regionalHostEnvPrefix) {
+			config.Regionals[strings.ToLower(strings.TrimPrefix(name, regionalHostEnvPrefix))] = value
+		}
+	}
+
+	return config, config.validate()
+}
+
+func (c HostsConfig) validate() error {
+	if len(c.Platforms) == 0 {
+		return fmt.Errorf("at least one platform host should be configured")
+	}
+	for _, hosts := range []map[string]string{c.Platforms, c.Regionals} {
+		for name, host := range hosts {
+			parsed, err := url.Parse(host)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+				return fmt.Errorf("invalid host %q for %s", host, name)
+			}
+		}
+	}
+	return nil
+}
+
+func splitEnv(env string) (string, string) {
+	parts := strings.SplitN(env, "=", 2)
+	if len(parts) < 2 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadHostsConfig(t *testing.T) {
+	t.Run("Test load hosts config without overrides returns rito hosts", func(t *testing.T) {
+		clearRitoEnv(t)
+
+		config, err := LoadHostsConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, GetRitoHosts(), config.Platforms)
+		assert.Equal(t, GetRitoRegionalHosts(), config.Regionals)
+	})
+
+	t.Run("Test load hosts config applies file, base url and single host overrides", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "hosts")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "hosts.json")
+		content := `{"platforms": {"euw1": "http://euw1.local", "na1": "http://na1.local"}}`
+		assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+		setEnv(t, "RITO_HOSTS_FILE", path)
+		setEnv(t, "RITO_BASE_URL", "http://emulator.local")
+		setEnv(t, "RITO_PLATFORM_HOST_NA1", "http://localhost:8081")
+
+		config, err := LoadHostsConfig()
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{
+			"euw1": "http://emulator.local",
+			"na1":  "http://localhost:8081",
+		}, config.Platforms)
+		assert.Equal(t, "http://emulator.local", config.Regionals["europe"])
+	})
+
+	t.Run("Test load hosts config with an invalid host returns an error", func(t *testing.T) {
+		setEnv(t, "RITO_PLATFORM_HOST_EUW1", "not a url")
+
+		_, err := LoadHostsConfig()
+		assert.NotNil(t, err)
+	})
+}
+
+// clearRitoEnv unsets the RITO_* variables of the environment running the tests until the test ends
+func clearRitoEnv(t *testing.T) {
+	for _, env := range os.Environ() {
+		name, value := splitEnv(env)
+		if !strings.HasPrefix(name, "RITO_") {
+			continue
+		}
+		assert.Nil(t, os.Unsetenv(name))
+		t.Cleanup(func() {
+			_ = os.Setenv(name, value)
+		})
+	}
+}
+
+func setEnv(t *testing.T, name string, value string) {
+	assert.Nil(t, os.Setenv(name, value))
+	t.Cleanup(func() {
+		_ = os.Unsetenv(name)
+	})
+}
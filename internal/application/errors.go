@@ -0,0 +1,54 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMatchNotFound is returned when the summoner is not in game
+var ErrMatchNotFound = errors.New("match not found")
+
+// ErrSummonerNotFound is returned when rito does not know the summoner name or id in the region
+var ErrSummonerNotFound = errors.New("summoner not found")
+
+// ErrArchivedGameNotFound is returned when the game was never looked up
+var ErrArchivedGameNotFound = errors.New("archived game not found")
+
+var ErrGroupNotFound = errors.New("group not found")
+
+// ErrClashTeamNotFound is returned when the summoner is not registered in a clash team
+var ErrClashTeamNotFound = errors.New("clash team not found")
+
+// ErrAPIClientNotFound is returned when the api key or client id is unknown
+var ErrAPIClientNotFound = errors.New("api client not found")
+
+// ErrRitoTokenRejected is returned when rito does not accept the configured token, development tokens expire daily
+var ErrRitoTokenRejected = errors.New("rito token can be expired")
+
+// UpstreamUnavailableError is returned without calling rito while the region is considered down
+type UpstreamUnavailableError struct {
+	Region string
+}
+
+func (e UpstreamUnavailableError) Error() string {
+	return fmt.Sprintf("rito api is unavailable for region %s", e.Region)
+}
+
+// UpstreamError is returned when rito answers with a status it was not expected to
+type UpstreamError struct {
+	Region string
+	Status int
+}
+
+func (e UpstreamError) Error() string {
+	return "uups, something went wrong"
+}
+
+// UnknownRegionError is returned when the region has no configured host
+type UnknownRegionError struct {
+	Region string
+}
+
+func (e UnknownRegionError) Error() string {
+	return fmt.Sprintf("unknown region %s", e.Region)
+}
@@ -0,0 +1,197 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"sync"
+	"time"
+)
+
+// leaderboardPeriod is how far back the LP change of the leaderboard goes
+const leaderboardPeriod = 7 * 24 * time.Hour
+
+type GroupService interface {
+	CreateGroup(name string) (*domain.Group, error)
+	FindGroup(groupId int64) (*domain.Group, error)
+	// AddMember also tracks the rank history of the summoner, which the leaderboard LP change is based on
+	AddMember(ctx context.Context, groupId int64, region string, summonerName string) (*domain.Group, error)
+	RemoveMember(groupId int64, region string, summonerId string) (*domain.Group, error)
+	// FindLeaderboard returns the latest refreshed leaderboard, building it when there is none yet
+	FindLeaderboard(ctx context.Context, groupId int64) (*domain.Leaderboard, error)
+	RefreshLeaderboards(ctx context.Context)
+}
+
+type groupService struct {
+	ritoProvider RitoProvider
+	groups       GroupRepository
+	rankHistory  RankHistoryRepository
+	mu           *sync.RWMutex
+	leaderboards map[int64]*domain.Leaderboard
+}
+
+func (g groupService) CreateGroup(name string) (*domain.Group, error) {
+	return g.groups.CreateGroup(domain.Group{
+		Name:      name,
+		CreatedAt: time.Now().UTC(),
+		Members:   []domain.GroupMember{},
+	})
+}
+
+func (g groupService) FindGroup(groupId int64) (*domain.Group, error) {
+	return g.groups.FindGroup(groupId)
+}
+
+func (g groupService) AddMember(ctx context.Context, groupId int64, region string, summonerName string) (*domain.Group, error) {
+	if _, err := g.groups.FindGroup(groupId); err != nil {
+		return nil, err
+	}
+	summonerDTO, err := g.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.groups.AddGroupMember(groupId, domain.GroupMember{
+		Region:       region,
+		SummonerId:   summonerDTO.Id,
+		SummonerName: summonerDTO.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tracked := domain.TrackedSummoner{
+		Region:       region,
+		SummonerId:   summonerDTO.Id,
+		SummonerName: summonerDTO.Name,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err = g.rankHistory.SaveTrackedSummoner(tracked); err != nil {
+		return nil, err
+	}
+	// as when tracking, the first snapshot is taken right away so the LP change starts counting from now
+	snapshotLeagues(ctx, g.ritoProvider, g.rankHistory, tracked)
+	g.invalidate(groupId)
+
+	return g.groups.FindGroup(groupId)
+}
+
+func (g groupService) RemoveMember(groupId int64, region string, summonerId string) (*domain.Group, error) {
+	if err := g.groups.RemoveGroupMember(groupId, region, summonerId); err != nil {
+		return nil, err
+	}
+	g.invalidate(groupId)
+
+	return g.groups.FindGroup(groupId)
+}
+
+func (g groupService) FindLeaderboard(ctx context.Context, groupId int64) (*domain.Leaderboard, error) {
+	g.mu.RLock()
+	leaderboard, exists := g.leaderboards[groupId]
+	g.mu.RUnlock()
+	if exists {
+		return leaderboard, nil
+	}
+
+	group, err := g.groups.FindGroup(groupId)
+	if err != nil {
+		return nil, err
+	}
+	return g.refresh(ctx, *group), nil
+}
+
+func (g groupService) RefreshLeaderboards(ctx context.Context) {
+	groups, err := g.groups.FindGroups()
+	if err != nil {
+		logging.FromContext(ctx).Error("could not load the groups", "err", err)
+		return
+	}
+	for _, group := range groups {
+		g.refresh(ctx, group)
+	}
+}
+
+func (g groupService) invalidate(groupId int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.leaderboards, groupId)
+}
+
+func (g groupService) refresh(ctx context.Context, group domain.Group) *domain.Leaderboard {
+	leaderboard := &domain.Leaderboard{
+		GroupId:     group.Id,
+		Name:        group.Name,
+		RefreshedAt: time.Now().UTC(),
+		Entries:     make([]domain.LeaderboardEntry, 0, len(group.Members)),
+	}
+	for _, member := range group.Members {
+		leaderboard.Entries = append(leaderboard.Entries, g.leaderboardEntry(ctx, member))
+	}
+	domain.SortLeaderboard(leaderboard.Entries)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.leaderboards[group.Id] = leaderboard
+	return leaderboard
+}
+
+func (g groupService) leaderboardEntry(ctx context.Context, member domain.GroupMember) domain.LeaderboardEntry {
+	entry := domain.LeaderboardEntry{GroupMember: member}
+	leaguesDTO, err := g.ritoProvider.FindLeaguesByRegionAndSummonerId(ctx, member.Region, member.SummonerId)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the leagues", "summoner_id", member.SummonerId, "err", err)
+		return entry
+	}
+	leagues := buildLeagues(leaguesDTO)
+	for i, league := range leagues {
+		if league.QueueType != domain.SoloQueueType {
+			continue
+		}
+		entry.SoloQueue = &leagues[i]
+		entry.GamesPlayed = league.Wins + league.Losses
+		entry.WinRate = league.WinRate
+	}
+	if entry.SoloQueue == nil {
+		return entry
+	}
+
+	baseline, err := g.leaderboardBaseline(member)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the rank history", "summoner_id", member.SummonerId, "err", err)
+		return entry
+	}
+	if baseline != nil {
+		entry.LPChange = domain.RankScore(entry.SoloQueue.Tier, entry.SoloQueue.Rank, entry.SoloQueue.LeaguePoints) -
+			domain.RankScore(baseline.Tier, baseline.Rank, baseline.LeaguePoints)
+	}
+	return entry
+}
+
+// leaderboardBaseline is the solo queue standing the LP change is counted from, the one at the start of the period.
+// A summoner tracked during the period has no standing back then, its first snapshot is used instead.
+func (g groupService) leaderboardBaseline(member domain.GroupMember) (*domain.LeagueSnapshot, error) {
+	since := time.Now().Add(-leaderboardPeriod)
+	baseline, err := g.rankHistory.FindLatestLeagueSnapshot(member.Region, member.SummonerId, domain.SoloQueueType, since)
+	if err != nil || baseline != nil {
+		return baseline, err
+	}
+	snapshots, err := g.rankHistory.FindLeagueSnapshots(member.Region, member.SummonerId, domain.SoloQueueType, since)
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
+
+func NewGroupService(provider RitoProvider, groups GroupRepository, rankHistory RankHistoryRepository) GroupService {
+	return groupService{
+		ritoProvider: provider,
+		groups:       groups,
+		rankHistory:  rankHistory,
+		mu:           &sync.RWMutex{},
+		leaderboards: make(map[int64]*domain.Leaderboard),
+	}
+}
+
+// NewLeaderboardScheduler refreshes the leaderboard of every group once per interval
+func NewLeaderboardScheduler(service GroupService, interval time.Duration) Scheduler {
+	return newPeriodicTask("leaderboards", interval, service.RefreshLeaderboards)
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestAddMember(t *testing.T) {
+	t.Run("Test a new member is tracked with a first league snapshot", func(t *testing.T) {
+		groups := &groupRepositoryMock{groups: map[int64]*domain.Group{1: {Id: 1, Name: "loleros"}}}
+		rankHistory := &rankHistoryRepositoryMock{}
+		service := NewGroupService(ritoProviderMock{}, groups, rankHistory)
+
+		group, err := service.AddMember(context.Background(), 1, "euw1", "xNibe")
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.GroupMember{{Region: "euw1", SummonerId: "xNibe_id", SummonerName: "xNibe"}}, group.Members)
+		assert.Len(t, rankHistory.tracked, 1)
+		assert.Len(t, rankHistory.snapshots, 1)
+		assert.Equal(t, "xNibe_id", rankHistory.snapshots[0].SummonerId)
+		assert.Equal(t, "GOLD", rankHistory.snapshots[0].League.Tier)
+	})
+	t.Run("Test a member is not added to a group that does not exist", func(t *testing.T) {
+		rankHistory := &rankHistoryRepositoryMock{}
+		service := NewGroupService(ritoProviderMock{}, &groupRepositoryMock{}, rankHistory)
+
+		_, err := service.AddMember(context.Background(), 999, "euw1", "xNibe")
+		assert.Equal(t, ErrGroupNotFound, err)
+		assert.Empty(t, rankHistory.tracked)
+		assert.Empty(t, rankHistory.snapshots)
+	})
+}
+
+func TestFindLeaderboard(t *testing.T) {
+	member := domain.GroupMember{Region: "euw1", SummonerId: "xNibe_id", SummonerName: "xNibe"}
+	tests := []struct {
+		name             string
+		snapshots        []domain.LeagueSnapshot
+		expectedLPChange int
+	}{
+		{
+			name: "Test the LP change counts from the standing before the period",
+			snapshots: []domain.LeagueSnapshot{
+				{Region: "euw1", SummonerId: "xNibe_id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "II", 50, 8, 10), TakenAt: time.Now().AddDate(0, 0, -10)},
+				{Region: "euw1", SummonerId: "xNibe_id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 0, 10, 10), TakenAt: time.Now().AddDate(0, 0, -2)},
+			},
+			expectedLPChange: 50,
+		}, {
+			name: "Test the LP change of a summoner tracked during the period counts from its first snapshot",
+			snapshots: []domain.LeagueSnapshot{
+				{Region: "euw1", SummonerId: "xNibe_id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "II", 80, 8, 10), TakenAt: time.Now().AddDate(0, 0, -3)},
+				{Region: "euw1", SummonerId: "xNibe_id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 0, 10, 10), TakenAt: time.Now().AddDate(0, 0, -2)},
+			},
+			expectedLPChange: 20,
+		}, {
+			name:             "Test the LP change is zero without rank history",
+			expectedLPChange: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := &groupRepositoryMock{groups: map[int64]*domain.Group{1: {Id: 1, Name: "loleros", Members: []domain.GroupMember{member}}}}
+			service := NewGroupService(ritoProviderMock{}, groups, &rankHistoryRepositoryMock{snapshots: tt.snapshots})
+
+			leaderboard, err := service.FindLeaderboard(context.Background(), 1)
+			assert.Nil(t, err)
+			assert.Len(t, leaderboard.Entries, 1)
+			assert.Equal(t, tt.expectedLPChange, leaderboard.Entries[0].LPChange)
+		})
+	}
+}
+
+type groupRepositoryMock struct {
+	groups map[int64]*domain.Group
+}
+
+func (g *groupRepositoryMock) CreateGroup(group domain.Group) (*domain.Group, error) {
+	return &group, nil
+}
+
+func (g *groupRepositoryMock) FindGroup(groupId int64) (*domain.Group, error) {
+	group, exists := g.groups[groupId]
+	if !exists {
+		return nil, ErrGroupNotFound
+	}
+	return group, nil
+}
+
+func (g *groupRepositoryMock) FindGroups() ([]domain.Group, error) {
+	return nil, nil
+}
+
+func (g *groupRepositoryMock) AddGroupMember(groupId int64, member domain.GroupMember) error {
+	g.groups[groupId].Members = append(g.groups[groupId].Members, member)
+	return nil
+}
+
+func (g *groupRepositoryMock) RemoveGroupMember(groupId int64, region string, summonerId string) error {
+	return nil
+}
+
+type rankHistoryRepositoryMock struct {
+	tracked   []domain.TrackedSummoner
+	snapshots []domain.LeagueSnapshot
+}
+
+func (r *rankHistoryRepositoryMock) SaveTrackedSummoner(summoner domain.TrackedSummoner) error {
+	r.tracked = append(r.tracked, summoner)
+	return nil
+}
+
+func (r *rankHistoryRepositoryMock) FindTrackedSummoners() ([]domain.TrackedSummoner, error) {
+	return r.tracked, nil
+}
+
+func (r *rankHistoryRepositoryMock) SaveLeagueSnapshot(snapshot domain.LeagueSnapshot) (bool, error) {
+	r.snapshots = append(r.snapshots, snapshot)
+	return true, nil
+}
+
+func (r *rankHistoryRepositoryMock) FindLeagueSnapshots(region string, summonerId string, queueType string, since time.Time) ([]domain.LeagueSnapshot, error) {
+	var snapshots []domain.LeagueSnapshot
+	for _, snapshot := range r.snapshots {
+		if snapshot.Region == region && snapshot.SummonerId == summonerId && snapshot.League.QueueType == queueType && !snapshot.TakenAt.Before(since) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots, nil
+}
+
+func (r *rankHistoryRepositoryMock) FindLatestLeagueSnapshot(region string, summonerId string, queueType string, at time.Time) (*domain.LeagueSnapshot, error) {
+	var latest *domain.LeagueSnapshot
+	for i, snapshot := range r.snapshots {
+		if snapshot.Region == region && snapshot.SummonerId == summonerId && snapshot.League.QueueType == queueType && !snapshot.TakenAt.After(at) &&
+			(latest == nil || snapshot.TakenAt.After(latest.TakenAt)) {
+			latest = &r.snapshots[i]
+		}
+	}
+	return latest, nil
+}
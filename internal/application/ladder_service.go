@@ -0,0 +1,165 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"sort"
+	"strings"
+)
+
+// apexTiers from the top of the ladder
+var apexTiers = []string{"CHALLENGER", "GRANDMASTER", "MASTER"}
+
+type LadderService interface {
+	// FindApexLadder returns a page, starting at 1, of an apex tier ordered by LP with the positions in the whole apex ladder
+	FindApexLadder(ctx context.Context, region string, queue string, tier string, page int, pageSize int) (*domain.Ladder, error)
+	FindLeagueEntries(ctx context.Context, region string, queue string, tier string, division string, page int) (*domain.Ladder, error)
+	FindStanding(ctx context.Context, region string, queue string, summonerId string) (*domain.LadderStanding, error)
+}
+
+type ladderService struct {
+	ritoProvider RitoProvider
+}
+
+func (l ladderService) FindApexLadder(ctx context.Context, region string, queue string, tier string, page int, pageSize int) (*domain.Ladder, error) {
+	tier = strings.ToUpper(tier)
+	ladder, err := l.apexLadder(ctx, region, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []domain.LadderEntry
+	for _, entry := range ladder {
+		if entry.Tier == tier {
+			entries = append(entries, entry)
+		}
+	}
+	total := len(entries)
+	start, end := (page-1)*pageSize, page*pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	return &domain.Ladder{
+		Region:  region,
+		Queue:   queue,
+		Tier:    tier,
+		Page:    page,
+		Total:   total,
+		Entries: append([]domain.LadderEntry{}, entries[start:end]...),
+	}, nil
+}
+
+func (l ladderService) FindLeagueEntries(ctx context.Context, region string, queue string, tier string, division string, page int) (*domain.Ladder, error) {
+	entriesDTO, err := l.ritoProvider.FindLeagueEntriesByRegionAndQueue(ctx, region, queue, tier, division, page)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.LadderEntry, 0, len(entriesDTO))
+	for _, entryDTO := range entriesDTO {
+		entries = append(entries, domain.LadderEntry{
+			SummonerId:   entryDTO.SummonerId,
+			SummonerName: entryDTO.SummonerName,
+			League: domain.NewLeague(
+				entryDTO.QueueType,
+				entryDTO.Tier,
+				entryDTO.Rank,
+				entryDTO.LeaguePoints,
+				entryDTO.Wins,
+				entryDTO.Losses,
+			),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LeaguePoints > entries[j].LeaguePoints
+	})
+
+	return &domain.Ladder{
+		Region:   region,
+		Queue:    queue,
+		Tier:     tier,
+		Division: division,
+		Page:     page,
+		Entries:  entries,
+	}, nil
+}
+
+func (l ladderService) FindStanding(ctx context.Context, region string, queue string, summonerId string) (*domain.LadderStanding, error) {
+	leaguesDTO, err := l.ritoProvider.FindLeaguesByRegionAndSummonerId(ctx, region, summonerId)
+	if err != nil {
+		return nil, err
+	}
+	standing := &domain.LadderStanding{SummonerId: summonerId}
+	leagues := buildLeagues(leaguesDTO)
+	for i, league := range leagues {
+		if league.QueueType == queue {
+			standing.League = &leagues[i]
+		}
+	}
+
+	ladder, err := l.apexLadder(ctx, region, queue)
+	if err != nil {
+		return nil, err
+	}
+	standing.ApexPlayers = len(ladder)
+	if standing.League == nil {
+		return standing, nil
+	}
+	if !domain.IsApexTier(standing.League.Tier) {
+		standing.PointsToApex = domain.PointsToApex(*standing.League)
+		return standing, nil
+	}
+	for _, entry := range ladder {
+		if entry.SummonerId == summonerId {
+			standing.Position = entry.Position
+		}
+	}
+	return standing, nil
+}
+
+// apexLadder joins the apex tiers ordered from the top, with the position of each entry
+func (l ladderService) apexLadder(ctx context.Context, region string, queue string) ([]domain.LadderEntry, error) {
+	var ladder []domain.LadderEntry
+	for _, tier := range apexTiers {
+		leagueDTO, err := l.ritoProvider.FindApexLeagueByRegionAndQueue(ctx, region, tier, queue)
+		if err != nil {
+			return nil, err
+		}
+		ladder = append(ladder, apexEntries(leagueDTO, tier, queue)...)
+	}
+	for i := range ladder {
+		ladder[i].Position = i + 1
+	}
+	return ladder, nil
+}
+
+func apexEntries(leagueDTO *providers.LeagueListDTO, tier string, queue string) []domain.LadderEntry {
+	entries := make([]domain.LadderEntry, 0, len(leagueDTO.Entries))
+	for _, itemDTO := range leagueDTO.Entries {
+		entries = append(entries, domain.LadderEntry{
+			SummonerId:   itemDTO.SummonerId,
+			SummonerName: itemDTO.SummonerName,
+			League: domain.NewLeague(
+				queue,
+				tier,
+				itemDTO.Rank,
+				itemDTO.LeaguePoints,
+				itemDTO.Wins,
+				itemDTO.Losses,
+			),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LeaguePoints > entries[j].LeaguePoints
+	})
+	return entries
+}
+
+func NewLadderService(provider RitoProvider) LadderService {
+	return ladderService{ritoProvider: provider}
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLadderServiceFindApexLadder(t *testing.T) {
+	provider := ritoProviderMock{
+		findApexLeague: func(tier string) *providers.LeagueListDTO {
+			switch tier {
+			case "CHALLENGER":
+				return &providers.LeagueListDTO{Tier: tier, Entries: []providers.LeagueItemDTO{
+					{SummonerId: "second", Rank: "I", LeaguePoints: 900},
+					{SummonerId: "first", Rank: "I", LeaguePoints: 1200},
+				}}
+			case "GRANDMASTER":
+				return &providers.LeagueListDTO{Tier: tier, Entries: []providers.LeagueItemDTO{
+					{SummonerId: "fourth", Rank: "I", LeaguePoints: 500},
+					{SummonerId: "third", Rank: "I", LeaguePoints: 700},
+					{SummonerId: "fifth", Rank: "I", LeaguePoints: 450},
+				}}
+			}
+			return &providers.LeagueListDTO{Tier: tier}
+		},
+	}
+	service := NewLadderService(provider)
+
+	t.Run("Test the apex ladder is ordered by LP with the positions after the higher tiers", func(t *testing.T) {
+		ladder, err := service.FindApexLadder(context.Background(), "test_region", "RANKED_SOLO_5x5", "grandmaster", 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, "GRANDMASTER", ladder.Tier)
+		assert.Equal(t, 3, ladder.Total)
+		assert.Len(t, ladder.Entries, 2)
+		assert.Equal(t, "third", ladder.Entries[0].SummonerId)
+		assert.Equal(t, 3, ladder.Entries[0].Position)
+		assert.Equal(t, "fourth", ladder.Entries[1].SummonerId)
+		assert.Equal(t, 4, ladder.Entries[1].Position)
+	})
+	t.Run("Test a page after the last entry of the apex ladder is empty", func(t *testing.T) {
+		ladder, err := service.FindApexLadder(context.Background(), "test_region", "RANKED_SOLO_5x5", "GRANDMASTER", 3, 2)
+		assert.Nil(t, err)
+		assert.Empty(t, ladder.Entries)
+	})
+	t.Run("Test the standing of a summoner below the apex tiers has the points to master", func(t *testing.T) {
+		standing, err := service.FindStanding(context.Background(), "test_region", "RANKED_SOLO_5x5", "test_id")
+		assert.Nil(t, err)
+		assert.Equal(t, "GOLD", standing.League.Tier)
+		assert.Equal(t, 0, standing.Position)
+		assert.Equal(t, 5, standing.ApexPlayers)
+		assert.Greater(t, standing.PointsToApex, 0)
+	})
+}
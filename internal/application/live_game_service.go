@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is the number of events kept for a slow subscriber before closing its subscription
+const subscriberBuffer = 8
+
+type LiveGameService interface {
+	// Subscribe streams the live game events of the summoner until unsubscribe is called
+	Subscribe(ctx context.Context, region string, summonerName string) (events <-chan domain.LiveGameEvent, unsubscribe func(), err error)
+	// Stop ends every watcher and closes the subscriptions
+	Stop()
+}
+
+type liveGameService struct {
+	ritoProvider RitoProvider
+	interval     time.Duration
+	mu           *sync.Mutex
+	watchers     map[string]*gameWatcher
+}
+
+// gameWatcher polls the spectator endpoint for a single summoner and fans out the events to every subscriber
+type gameWatcher struct {
+	region      string
+	summonerId  string
+	stop        chan struct{}
+	subscribers map[chan domain.LiveGameEvent]bool
+	// last is the latest game event, replayed to new subscribers
+	last *domain.LiveGameEvent
+}
+
+func (l liveGameService) Subscribe(ctx context.Context, region string, summonerName string) (<-chan domain.LiveGameEvent, func(), error) {
+	summonerDTO, err := l.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := region + "_" + summonerDTO.Id
+	events := make(chan domain.LiveGameEvent, subscriberBuffer)
+
+	l.mu.Lock()
+	watcher, exists := l.watchers[key]
+	if !exists {
+		watcher = &gameWatcher{
+			region:      region,
+			summonerId:  summonerDTO.Id,
+			stop:        make(chan struct{}),
+			subscribers: make(map[chan domain.LiveGameEvent]bool),
+		}
+		l.watchers[key] = watcher
+		go l.watch(watcher)
+	}
+	watcher.subscribers[events] = true
+	if watcher.last != nil {
+		events <- *watcher.last
+	}
+	l.mu.Unlock()
+
+	unsubscribe := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.remove(watcher, events)
+	}
+
+	return events, unsubscribe, nil
+}
+
+// remove closes the subscription, stopping the watcher after its last subscriber. The lock has to be held.
+func (l liveGameService) remove(watcher *gameWatcher, events chan domain.LiveGameEvent) {
+	if !watcher.subscribers[events] {
+		return
+	}
+	delete(watcher.subscribers, events)
+	close(events)
+	if len(watcher.subscribers) == 0 {
+		close(watcher.stop)
+		delete(l.watchers, watcher.region+"_"+watcher.summonerId)
+	}
+}
+
+func (l liveGameService) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, watcher := range l.watchers {
+		close(watcher.stop)
+		for events := range watcher.subscribers {
+			close(events)
+		}
+		watcher.subscribers = nil
+		delete(l.watchers, key)
+	}
+}
+
+func (l liveGameService) watch(watcher *gameWatcher) {
+	// the watcher outlives the request that started it
+	ctx := taskContext("live-game")
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	var current *domain.Match
+	for {
+		current = l.poll(ctx, watcher, current)
+		select {
+		case <-watcher.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll checks the active game of the watched summoner and publishes the transition, returning the game in progress
+func (l liveGameService) poll(ctx context.Context, watcher *gameWatcher, current *domain.Match) *domain.Match {
+	matchDTO, err := l.ritoProvider.FindMatchBySummonerId(ctx, watcher.region, watcher.summonerId)
+	switch {
+	case errors.Is(err, ErrMatchNotFound):
+		if current != nil {
+			l.publish(ctx, watcher, domain.LiveGameEvent{Type: domain.LiveGameEnded, GameId: current.GameId})
+		}
+		return nil
+	case err != nil:
+		logging.FromContext(ctx).Warn("could not poll the active game", "summoner_id", watcher.summonerId, "err", err)
+		return current
+	}
+
+	if current == nil || current.GameId != matchDTO.GameId {
+		current = buildMatch(ctx, l.ritoProvider, watcher.region, matchDTO)
+		l.publish(ctx, watcher, domain.LiveGameEvent{
+			Type:           domain.LiveGameStarted,
+			GameId:         current.GameId,
+			ElapsedSeconds: elapsedSeconds(matchDTO),
+			Match:          current,
+		})
+		return current
+	}
+
+	l.publish(ctx, watcher, domain.LiveGameEvent{
+		Type:           domain.LiveGameUpdated,
+		GameId:         current.GameId,
+		ElapsedSeconds: elapsedSeconds(matchDTO),
+	})
+	return current
+}
+
+func (l liveGameService) publish(ctx context.Context, watcher *gameWatcher, event domain.LiveGameEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch event.Type {
+	case domain.LiveGameStarted:
+		watcher.last = &event
+	case domain.LiveGameEnded:
+		watcher.last = nil
+	}
+	for events := range watcher.subscribers {
+		select {
+		case events <- event:
+		default:
+			// the subscriber is not keeping up, dropping the event could leave it showing a finished game as live.
+			// Its subscription is closed instead, subscribing again replays the latest game event.
+			logging.FromContext(ctx).Warn("closing a live game subscription not keeping up", "summoner_id", watcher.summonerId)
+			l.remove(watcher, events)
+		}
+	}
+}
+
+func elapsedSeconds(matchDTO *providers.MatchDTO) int64 {
+	if matchDTO.GameStartTime == 0 {
+		return 0
+	}
+	return int64(time.Since(time.Unix(0, matchDTO.GameStartTime*int64(time.Millisecond))).Seconds())
+}
+
+// NewLiveGameService polls rito once per interval for each watched summoner, no matter the subscribers
+func NewLiveGameService(provider RitoProvider, interval time.Duration) LiveGameService {
+	return liveGameService{
+		ritoProvider: provider,
+		interval:     interval,
+		mu:           &sync.Mutex{},
+		watchers:     make(map[string]*gameWatcher),
+	}
+}
@@ -0,0 +1,176 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLiveGameServiceSubscribe(t *testing.T) {
+	t.Run("Test subscribers share a single poll and get the game lifecycle", func(t *testing.T) {
+		var mu sync.Mutex
+		polls := 0
+		provider := ritoProviderMock{
+			findMatchBySummonerId: func(region string, summonerId string) (*providers.MatchDTO, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				polls++
+				if polls > 2 {
+					return nil, ErrMatchNotFound
+				}
+				return &providers.MatchDTO{
+					GameId:       1,
+					Participants: []providers.ParticipantDTO{{TeamId: 100, SummonerId: "participant_id"}},
+				}, nil
+			},
+		}
+		service := NewLiveGameService(provider, 20*time.Millisecond)
+		defer service.Stop()
+
+		first, unsubscribeFirst, err := service.Subscribe(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond, err := service.Subscribe(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		defer unsubscribeSecond()
+
+		for _, events := range []<-chan domain.LiveGameEvent{first, second} {
+			var types []string
+			for len(types) < 3 {
+				select {
+				case event := <-events:
+					if event.Type == domain.LiveGameStarted {
+						assert.Len(t, event.Match.Summoners, 1)
+					}
+					types = append(types, event.Type)
+				case <-time.After(time.Second):
+					t.Fatal("timeout waiting for live game events")
+				}
+			}
+			assert.Equal(t, []string{domain.LiveGameStarted, domain.LiveGameUpdated, domain.LiveGameEnded}, types)
+		}
+		mu.Lock()
+		assert.GreaterOrEqual(t, polls, 3)
+		assert.Less(t, polls, 6)
+		mu.Unlock()
+	})
+	t.Run("Test a subscriber not keeping up is closed and gets the game again when subscribing back", func(t *testing.T) {
+		provider := ritoProviderMock{
+			findMatchBySummonerId: func(region string, summonerId string) (*providers.MatchDTO, error) {
+				return &providers.MatchDTO{GameId: 1}, nil
+			},
+		}
+		service := NewLiveGameService(provider, time.Millisecond)
+		defer service.Stop()
+
+		slow, unsubscribeSlow, err := service.Subscribe(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		defer unsubscribeSlow()
+		// the subscriber does not read until its buffer is full and its subscription closed
+		time.Sleep(50 * time.Millisecond)
+		received := 0
+		for range slow {
+			received++
+		}
+		assert.Equal(t, subscriberBuffer, received)
+
+		again, unsubscribeAgain, err := service.Subscribe(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		defer unsubscribeAgain()
+		select {
+		case event := <-again:
+			assert.Equal(t, domain.LiveGameStarted, event.Type)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the live game")
+		}
+	})
+}
+
+type ritoProviderMock struct {
+	findMatchBySummonerId func(region string, summonerId string) (*providers.MatchDTO, error)
+	findApexLeague        func(tier string) *providers.LeagueListDTO
+	findFeaturedMatches   func() (*providers.FeaturedGamesDTO, error)
+	findSummonerByName    func(region string, name string) (*providers.SummonerDTO, error)
+	checkToken            func(ctx context.Context) error
+	circuitBreakerStates  map[string]string
+}
+
+func (r ritoProviderMock) FindSummonerByRegionAndName(ctx context.Context, region string, name string) (*providers.SummonerDTO, error) {
+	if r.findSummonerByName != nil {
+		return r.findSummonerByName(region, name)
+	}
+	return &providers.SummonerDTO{Id: name + "_id", Name: name}, nil
+}
+
+func (r ritoProviderMock) FindMatchBySummonerId(ctx context.Context, region string, summonerId string) (*providers.MatchDTO, error) {
+	return r.findMatchBySummonerId(region, summonerId)
+}
+
+func (r ritoProviderMock) FindSummonerByRegionAndId(ctx context.Context, region string, id string) (*providers.SummonerDTO, error) {
+	return &providers.SummonerDTO{Id: id, Name: id + "_name"}, nil
+}
+
+func (r ritoProviderMock) FindLeaguesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.LeagueInfoDTO, error) {
+	return []providers.LeagueInfoDTO{{QueueType: "RANKED_SOLO_5x5", Tier: "GOLD", Rank: "I", Wins: 10, Losses: 10}}, nil
+}
+
+func (r ritoProviderMock) FindFeaturedMatchesByRegion(ctx context.Context, region string) (*providers.FeaturedGamesDTO, error) {
+	return r.findFeaturedMatches()
+}
+
+func (r ritoProviderMock) FindChampionMasteriesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ChampionMasteryDTO, error) {
+	return []providers.ChampionMasteryDTO{}, nil
+}
+
+func (r ritoProviderMock) FindClashPlayersByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ClashPlayerDTO, error) {
+	return []providers.ClashPlayerDTO{}, nil
+}
+
+func (r ritoProviderMock) FindClashPlayersByRegionAndPuuid(ctx context.Context, region string, puuid string) ([]providers.ClashPlayerDTO, error) {
+	return []providers.ClashPlayerDTO{}, nil
+}
+
+func (r ritoProviderMock) FindClashTeamByRegionAndId(ctx context.Context, region string, teamId string) (*providers.ClashTeamDTO, error) {
+	return nil, ErrClashTeamNotFound
+}
+
+func (r ritoProviderMock) FindClashTournamentsByRegion(ctx context.Context, region string) ([]providers.ClashTournamentDTO, error) {
+	return []providers.ClashTournamentDTO{}, nil
+}
+
+func (r ritoProviderMock) FindApexLeagueByRegionAndQueue(ctx context.Context, region string, tier string, queue string) (*providers.LeagueListDTO, error) {
+	if r.findApexLeague != nil {
+		return r.findApexLeague(tier), nil
+	}
+	return &providers.LeagueListDTO{Tier: tier, Queue: queue}, nil
+}
+
+func (r ritoProviderMock) FindLeagueEntriesByRegionAndQueue(ctx context.Context, region string, queue string, tier string, division string, page int) ([]providers.LeagueInfoDTO, error) {
+	return []providers.LeagueInfoDTO{}, nil
+}
+
+func (r ritoProviderMock) FindPlatformStatusByRegion(ctx context.Context, region string) (*providers.PlatformDataDTO, error) {
+	return &providers.PlatformDataDTO{Id: region}, nil
+}
+
+func (r ritoProviderMock) CircuitBreakerStates() map[string]string {
+	if r.circuitBreakerStates != nil {
+		return r.circuitBreakerStates
+	}
+	return map[string]string{}
+}
+
+func (r ritoProviderMock) CheckToken(ctx context.Context) error {
+	if r.checkToken != nil {
+		return r.checkToken(ctx)
+	}
+	return nil
+}
+
+func (r ritoProviderMock) CheckCache() error {
+	return nil
+}
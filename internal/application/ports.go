@@ -1,10 +1,107 @@
 package application
 
-import providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"time"
+)
 
 type RitoProvider interface {
-	FindSummonerByRegionAndName(region string, name string) (*providers.SummonerDTO, error)
-	FindMatchBySummonerId(region string, summonerId string) (*providers.MatchDTO, error)
-	FindSummonerByRegionAndId(region string, id string) (*providers.SummonerDTO, error)
-	FindLeaguesByRegionAndSummonerId(region string, summonerId string) ([]providers.LeagueInfoDTO, error)
+	FindSummonerByRegionAndName(ctx context.Context, region string, name string) (*providers.SummonerDTO, error)
+	FindMatchBySummonerId(ctx context.Context, region string, summonerId string) (*providers.MatchDTO, error)
+	FindSummonerByRegionAndId(ctx context.Context, region string, id string) (*providers.SummonerDTO, error)
+	FindLeaguesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.LeagueInfoDTO, error)
+	FindFeaturedMatchesByRegion(ctx context.Context, region string) (*providers.FeaturedGamesDTO, error)
+	// FindChampionMasteriesByRegionAndSummonerId returns the masteries with the most points first
+	FindChampionMasteriesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ChampionMasteryDTO, error)
+	// FindClashPlayersByRegionAndSummonerId returns the active clash registrations of the summoner, none when not registered
+	FindClashPlayersByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ClashPlayerDTO, error)
+	FindClashPlayersByRegionAndPuuid(ctx context.Context, region string, puuid string) ([]providers.ClashPlayerDTO, error)
+	FindClashTeamByRegionAndId(ctx context.Context, region string, teamId string) (*providers.ClashTeamDTO, error)
+	FindClashTournamentsByRegion(ctx context.Context, region string) ([]providers.ClashTournamentDTO, error)
+	// FindApexLeagueByRegionAndQueue returns the challenger, grandmaster or master league of the queue
+	FindApexLeagueByRegionAndQueue(ctx context.Context, region string, tier string, queue string) (*providers.LeagueListDTO, error)
+	// FindLeagueEntriesByRegionAndQueue returns a page, starting at 1, of the entries of a tier and division
+	FindLeagueEntriesByRegionAndQueue(ctx context.Context, region string, queue string, tier string, division string, page int) ([]providers.LeagueInfoDTO, error)
+	FindPlatformStatusByRegion(ctx context.Context, region string) (*providers.PlatformDataDTO, error)
+	// CircuitBreakerStates returns the circuit breaker state of each region and regional host
+	CircuitBreakerStates() map[string]string
+	// CheckToken asks rito, skipping the cache, whether the token is accepted, returning ErrRitoTokenRejected when it is not
+	CheckToken(ctx context.Context) error
+	// CheckCache writes a probe entry to the response cache and reads it back
+	CheckCache() error
 }
+
+// Pinger checks the connection to a dependency, like the persistence store
+type Pinger interface {
+	Ping() error
+}
+
+type WatchlistRepository interface {
+	SaveWatchedSummoner(summoner domain.WatchedSummoner) error
+	// UpdateWatchedGame stores the game in progress of a summoner still in the watchlist
+	UpdateWatchedGame(region string, summonerId string, gameId int64) error
+	DeleteWatchedSummoner(region string, summonerId string) error
+	FindWatchedSummoners() ([]domain.WatchedSummoner, error)
+}
+
+type DeliveryRepository interface {
+	SaveDelivery(delivery domain.WebhookDelivery) error
+	// FindDeliveries returns the latest deliveries first
+	FindDeliveries(limit int) ([]domain.WebhookDelivery, error)
+}
+
+// Notifier delivers the watch events outside, failures are expected to be handled and logged by the notifier
+type Notifier interface {
+	Notify(event domain.WatchEvent)
+}
+
+type RankHistoryRepository interface {
+	SaveTrackedSummoner(summoner domain.TrackedSummoner) error
+	FindTrackedSummoners() ([]domain.TrackedSummoner, error)
+	// SaveLeagueSnapshot stores the snapshot unless the standing did not change since the latest one, telling if it was stored
+	SaveLeagueSnapshot(snapshot domain.LeagueSnapshot) (bool, error)
+	// FindLeagueSnapshots returns the snapshots taken since the given time, oldest first
+	FindLeagueSnapshots(region string, summonerId string, queueType string, since time.Time) ([]domain.LeagueSnapshot, error)
+	// FindLatestLeagueSnapshot returns the standing at the given time, the latest snapshot taken until then, nil when there is none.
+	// As the snapshots are only stored on changes, it is the baseline of the snapshots found since that time.
+	FindLatestLeagueSnapshot(region string, summonerId string, queueType string, at time.Time) (*domain.LeagueSnapshot, error)
+}
+
+type MatchArchiveRepository interface {
+	// ArchiveMatch stores the match unless its game was already archived
+	ArchiveMatch(game domain.ArchivedGame) error
+	// FindArchivedGames returns the games of the summoner, latest first
+	FindArchivedGames(region string, summonerId string, limit int) ([]domain.ArchivedGame, error)
+	// FindArchivedGame returns ErrArchivedGameNotFound when the game was never archived
+	FindArchivedGame(region string, gameId int64) (*domain.ArchivedGame, error)
+}
+
+type GroupRepository interface {
+	CreateGroup(group domain.Group) (*domain.Group, error)
+	// FindGroup returns ErrGroupNotFound when the group does not exist
+	FindGroup(groupId int64) (*domain.Group, error)
+	FindGroups() ([]domain.Group, error)
+	AddGroupMember(groupId int64, member domain.GroupMember) error
+	RemoveGroupMember(groupId int64, region string, summonerId string) error
+}
+
+type APIClientRepository interface {
+	SaveAPIClient(client domain.APIClient) error
+	// DeleteAPIClient returns ErrAPIClientNotFound when there is no client with the id
+	DeleteAPIClient(id string) error
+	FindAPIClients() ([]domain.APIClient, error)
+	// FindAPIClientByKey returns ErrAPIClientNotFound when no client has the key
+	FindAPIClientByKey(key string) (*domain.APIClient, error)
+}
+
+// Metrics observes the work of the services
+type Metrics interface {
+	// ObserveFanOut records the goroutines started by an operation to enrich the match participants
+	ObserveFanOut(operation string, goroutines int)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveFanOut(string, int) {}
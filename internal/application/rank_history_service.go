@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"time"
+)
+
+type RankHistoryService interface {
+	// Track adds the summoner to the ones snapshotted periodically
+	Track(ctx context.Context, region string, summonerName string) (*domain.TrackedSummoner, error)
+	FindRankHistory(region string, summonerId string, queueType string, since time.Time) ([]domain.RankHistoryPoint, error)
+}
+
+type rankHistoryService struct {
+	ritoProvider RitoProvider
+	repository   RankHistoryRepository
+}
+
+func (r rankHistoryService) Track(ctx context.Context, region string, summonerName string) (*domain.TrackedSummoner, error) {
+	summonerDTO, err := r.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, err
+	}
+	tracked := domain.TrackedSummoner{
+		Region:       region,
+		SummonerId:   summonerDTO.Id,
+		SummonerName: summonerDTO.Name,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err = r.repository.SaveTrackedSummoner(tracked); err != nil {
+		return nil, err
+	}
+	// the first snapshot is taken right away so the history starts when tracking does
+	snapshotLeagues(ctx, r.ritoProvider, r.repository, tracked)
+	return &tracked, nil
+}
+
+func (r rankHistoryService) FindRankHistory(region string, summonerId string, queueType string, since time.Time) ([]domain.RankHistoryPoint, error) {
+	snapshots, err := r.repository.FindLeagueSnapshots(region, summonerId, queueType, since)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewRankHistory(snapshots), nil
+}
+
+func NewRankHistoryService(provider RitoProvider, repository RankHistoryRepository) RankHistoryService {
+	return rankHistoryService{ritoProvider: provider, repository: repository}
+}
+
+// NewRankHistoryScheduler snapshots the leagues of every tracked summoner once per interval
+func NewRankHistoryScheduler(provider RitoProvider, repository RankHistoryRepository, interval time.Duration) Scheduler {
+	return newPeriodicTask("rank-history", interval, func(ctx context.Context) {
+		tracked, err := repository.FindTrackedSummoners()
+		if err != nil {
+			logging.FromContext(ctx).Error("could not load the tracked summoners", "err", err)
+			return
+		}
+		for _, summoner := range tracked {
+			snapshotLeagues(ctx, provider, repository, summoner)
+		}
+	})
+}
+
+func snapshotLeagues(ctx context.Context, provider RitoProvider, repository RankHistoryRepository, summoner domain.TrackedSummoner) {
+	leaguesDTO, err := provider.FindLeaguesByRegionAndSummonerId(ctx, summoner.Region, summoner.SummonerId)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not snapshot the leagues", "summoner_id", summoner.SummonerId, "err", err)
+		return
+	}
+	takenAt := time.Now().UTC()
+	for _, league := range buildLeagues(leaguesDTO) {
+		_, err = repository.SaveLeagueSnapshot(domain.LeagueSnapshot{
+			Region:     summoner.Region,
+			SummonerId: summoner.SummonerId,
+			League:     league,
+			TakenAt:    takenAt,
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("could not save the league snapshot", "summoner_id", summoner.SummonerId, "err", err)
+		}
+	}
+}
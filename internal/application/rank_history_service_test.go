@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTrack(t *testing.T) {
+	t.Run("Test a tracked summoner is snapshotted right away", func(t *testing.T) {
+		repository := &rankHistoryRepositoryMock{}
+
+		tracked, err := NewRankHistoryService(ritoProviderMock{}, repository).Track(context.Background(), "euw1", "xNibe")
+		assert.Nil(t, err)
+		assert.Equal(t, "xNibe_id", tracked.SummonerId)
+		assert.Equal(t, []domain.TrackedSummoner{*tracked}, repository.tracked)
+		assert.Len(t, repository.snapshots, 1)
+		assert.Equal(t, domain.SoloQueueType, repository.snapshots[0].League.QueueType)
+	})
+	t.Run("Test an unknown summoner is not tracked", func(t *testing.T) {
+		repository := &rankHistoryRepositoryMock{}
+		provider := ritoProviderMock{findSummonerByName: func(region string, name string) (*providers.SummonerDTO, error) {
+			return nil, ErrSummonerNotFound
+		}}
+
+		_, err := NewRankHistoryService(provider, repository).Track(context.Background(), "euw1", "nobody")
+		assert.Equal(t, ErrSummonerNotFound, err)
+		assert.Empty(t, repository.tracked)
+		assert.Empty(t, repository.snapshots)
+	})
+}
+
+func TestFindRankHistory(t *testing.T) {
+	t.Run("Test the history has the changes between the snapshots of the queue since the given time", func(t *testing.T) {
+		now := time.Now().UTC()
+		repository := &rankHistoryRepositoryMock{snapshots: []domain.LeagueSnapshot{
+			{Region: "euw1", SummonerId: "id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 10, 1, 0), TakenAt: now.Add(-72 * time.Hour)},
+			{Region: "euw1", SummonerId: "id", League: domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 90, 9, 1), TakenAt: now.Add(-2 * time.Hour)},
+			{Region: "euw1", SummonerId: "id", League: domain.NewLeague(domain.FlexQueueType, "SILVER", "II", 50, 3, 3), TakenAt: now.Add(-2 * time.Hour)},
+			{Region: "euw1", SummonerId: "id", League: domain.NewLeague(domain.SoloQueueType, "PLATINUM", "IV", 0, 10, 1), TakenAt: now.Add(-time.Hour)},
+		}}
+
+		history, err := NewRankHistoryService(ritoProviderMock{}, repository).
+			FindRankHistory("euw1", "id", domain.SoloQueueType, now.Add(-24*time.Hour))
+		assert.Nil(t, err)
+		assert.Len(t, history, 2)
+		assert.Equal(t, 0, history[0].LPChange)
+		assert.Equal(t, "PLATINUM", history[1].Tier)
+		assert.Equal(t, 10, history[1].LPChange, "the LP won across the tiers is counted")
+		assert.Equal(t, domain.RankPromotion, history[1].Change)
+	})
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"sync"
+	"time"
+)
+
+// Scheduler is a background job, Stop waits for the run in progress to finish
+type Scheduler interface {
+	Start()
+	Stop()
+}
+
+// periodicTask runs a function right away and then once per interval until stopped
+type periodicTask struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context)
+	stop     chan struct{}
+	done     chan struct{}
+	once     *sync.Once
+}
+
+func newPeriodicTask(name string, interval time.Duration, run func(ctx context.Context)) *periodicTask {
+	return &periodicTask{
+		name:     name,
+		interval: interval,
+		run:      run,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		once:     &sync.Once{},
+	}
+}
+
+func (t *periodicTask) Start() {
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			t.run(taskContext(t.name))
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (t *periodicTask) Stop() {
+	t.once.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+}
+
+// taskContext identifies each run of a background task in its logs, as the request id does for the requests
+func taskContext(name string) context.Context {
+	ctx := logging.NewContext(context.Background(), logging.Default().With("task", name))
+	return logging.WithRequestId(ctx, logging.NewRequestId())
+}
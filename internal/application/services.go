@@ -1,37 +1,130 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
 	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
-	"log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"sync"
 	"time"
 )
 
+var tracer = otel.Tracer("github.com/emipochettino/loleros-api/internal/application")
+
+// enrichmentConcurrency bounds the rito requests in flight while enriching the featured games,
+// they are a few games with ten participants each
+const enrichmentConcurrency = 10
+
 type matchService struct {
 	ritoProvider RitoProvider
+	archive      MatchArchiveRepository
+	metrics      Metrics
 	mu           *sync.Mutex
 }
 
 type MatchService interface {
-	FindCurrentMatchByRegionAndSummonerName(region string, summonerName string) (*domain.Match, error)
+	FindCurrentMatchByRegionAndSummonerName(ctx context.Context, region string, summonerName string) (*domain.Match, error)
+	// FindFeaturedMatchesByRegion enriches the featured games of the region the same way as the current match
+	FindFeaturedMatchesByRegion(ctx context.Context, region string) (*domain.FeaturedMatches, error)
+	FindArchivedGames(region string, summonerId string, limit int) ([]domain.ArchivedGame, error)
+	FindArchivedGame(region string, gameId int64) (*domain.ArchivedGame, error)
 }
 
-func (m matchService) FindCurrentMatchByRegionAndSummonerName(region string, summonerName string) (*domain.Match, error) {
+func (m matchService) FindCurrentMatchByRegionAndSummonerName(ctx context.Context, region string, summonerName string) (*domain.Match, error) {
+	ctx, span := tracer.Start(ctx, "matchService.FindCurrentMatchByRegionAndSummonerName", trace.WithAttributes(
+		attribute.String("region", region),
+		attribute.String("summoner_name", summonerName),
+	))
+	defer span.End()
+
 	start := time.Now()
-	summonerDTO, err := m.ritoProvider.FindSummonerByRegionAndName(region, summonerName)
+	summonerDTO, err := m.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	matchDTO, err := m.ritoProvider.FindMatchBySummonerId(ctx, region, summonerDTO.Id)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
+	span.SetAttributes(attribute.Int64("game_id", matchDTO.GameId))
+
+	m.metrics.ObserveFanOut("current_match", len(matchDTO.Participants))
+	match := buildMatch(ctx, m.ritoProvider, region, matchDTO)
+	m.archiveMatch(ctx, region, match)
+
+	logging.FromContext(ctx).Debug("current match built", "game_id", match.GameId, "elapsed", time.Since(start).String())
+	return match, nil
+}
 
-	matchDTO, err := m.ritoProvider.FindMatchBySummonerId(region, summonerDTO.Id)
+func (m matchService) FindFeaturedMatchesByRegion(ctx context.Context, region string) (*domain.FeaturedMatches, error) {
+	ctx, span := tracer.Start(ctx, "matchService.FindFeaturedMatchesByRegion", trace.WithAttributes(attribute.String("region", region)))
+	defer span.End()
+
+	featuredDTO, err := m.ritoProvider.FindFeaturedMatchesByRegion(ctx, region)
 	if err != nil {
 		return nil, err
 	}
 
-	//TODO make this async
-	//new approach: create waitGroup, add 1 in each iteration before go func, results add into other chan, wait
+	featured := &domain.FeaturedMatches{
+		Region:                 region,
+		RefreshIntervalSeconds: featuredDTO.ClientRefreshInterval,
+		Matches:                make([]domain.Match, len(featuredDTO.GameList)),
+	}
+	goroutines := len(featuredDTO.GameList)
+	for _, gameDTO := range featuredDTO.GameList {
+		goroutines += len(gameDTO.Participants)
+	}
+	m.metrics.ObserveFanOut("featured_matches", goroutines)
+	slots := make(chan struct{}, enrichmentConcurrency)
+	var wg sync.WaitGroup
+	wg.Add(len(featuredDTO.GameList))
+	for i := range featuredDTO.GameList {
+		go func(i int) {
+			defer wg.Done()
+			featured.Matches[i] = *enrichMatch(ctx, m.ritoProvider, region, &featuredDTO.GameList[i], slots)
+		}(i)
+	}
+	wg.Wait()
+
+	return featured, nil
+}
+
+func (m matchService) FindArchivedGames(region string, summonerId string, limit int) ([]domain.ArchivedGame, error) {
+	return m.archive.FindArchivedGames(region, summonerId, limit)
+}
+
+func (m matchService) FindArchivedGame(region string, gameId int64) (*domain.ArchivedGame, error) {
+	return m.archive.FindArchivedGame(region, gameId)
+}
+
+// archiveMatch keeps the match once its cache expires, a failure does not fail the lookup
+func (m matchService) archiveMatch(ctx context.Context, region string, match *domain.Match) {
+	err := m.archive.ArchiveMatch(domain.ArchivedGame{
+		Region:     region,
+		ArchivedAt: time.Now().UTC(),
+		Match:      *match,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("could not archive the game", "game_id", match.GameId, "err", err)
+	}
+}
+
+// buildMatch enriches each participant of the active game with its summoner and leagues
+func buildMatch(ctx context.Context, ritoProvider RitoProvider, region string, matchDTO *providers.MatchDTO) *domain.Match {
+	return enrichMatch(ctx, ritoProvider, region, matchDTO, nil)
+}
+
+// enrichMatch is buildMatch holding one of the slots while enriching a participant, without limit when slots is nil.
+// Participants without summoner id, as the ones of the featured games, are found by name.
+func enrichMatch(ctx context.Context, ritoProvider RitoProvider, region string, matchDTO *providers.MatchDTO, slots chan struct{}) *domain.Match {
 	summoners := make(chan domain.Summoner, len(matchDTO.Participants))
 	var wg sync.WaitGroup
 	// add the number of summoners in the match
@@ -39,35 +132,41 @@ func (m matchService) FindCurrentMatchByRegionAndSummonerName(region string, sum
 	for _, participant := range matchDTO.Participants {
 		go func(participant providers.ParticipantDTO) {
 			defer wg.Done()
-			summonerDTO, err := m.ritoProvider.FindSummonerByRegionAndId(region, participant.SummonerId)
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					// the caller is gone, nobody is waiting for the participant
+					return
+				}
+			}
+			// a span per participant shows which lookup is the slowest one
+			ctx, span := tracer.Start(ctx, "enrich participant", trace.WithAttributes(
+				attribute.String("summoner_id", participant.SummonerId),
+				attribute.String("summoner_name", participant.SummonerName),
+				attribute.Int64("team_id", participant.TeamId),
+			))
+			defer span.End()
+			summonerDTO, err := findParticipant(ctx, ritoProvider, region, participant)
 			if err != nil {
-				log.Println(err)
+				span.RecordError(err)
+				logging.FromContext(ctx).Warn("could not find the participant", "summoner_name", participant.SummonerName, "err", err)
 				return
 			}
-			leaguesDTO, err := m.ritoProvider.FindLeaguesByRegionAndSummonerId(region, summonerDTO.Id)
+			leaguesDTO, err := ritoProvider.FindLeaguesByRegionAndSummonerId(ctx, region, summonerDTO.Id)
 			if err != nil {
+				span.RecordError(err)
 				return
 			}
 
-			var leagues []domain.League
-			for _, leagueDTO := range leaguesDTO {
-				leagues = append(
-					leagues,
-					domain.NewLeague(
-						leagueDTO.QueueType,
-						leagueDTO.Tier,
-						leagueDTO.Rank,
-						leagueDTO.Wins,
-						leagueDTO.Losses,
-					))
-			}
-
 			summoner := domain.NewSummoner(
 				summonerDTO.Id,
 				summonerDTO.Name,
 				summonerDTO.Level,
 				participant.TeamId,
-				leagues,
+				participant.ChampionId,
+				buildLeagues(leaguesDTO),
 			)
 			summoners <- summoner
 		}(participant)
@@ -79,10 +178,160 @@ func (m matchService) FindCurrentMatchByRegionAndSummonerName(region string, sum
 		summonerSlice = append(summonerSlice, summoner)
 	}
 
-	print(fmt.Sprintf("\ntime: %.2f seconds\n", time.Now().Sub(start).Seconds()))
-	return &domain.Match{Summoners: summonerSlice}, nil
+	return &domain.Match{
+		GameId:        matchDTO.GameId,
+		QueueId:       matchDTO.GameQueueConfigId,
+		GameStartTime: matchDTO.GameStartTime,
+		Summoners:     summonerSlice,
+	}
+}
+
+func findParticipant(ctx context.Context, ritoProvider RitoProvider, region string, participant providers.ParticipantDTO) (*providers.SummonerDTO, error) {
+	if participant.SummonerId == "" {
+		return ritoProvider.FindSummonerByRegionAndName(ctx, region, participant.SummonerName)
+	}
+	return ritoProvider.FindSummonerByRegionAndId(ctx, region, participant.SummonerId)
+}
+
+// NewMatchService observes the enrichment fan-out in metrics, which can be nil
+func NewMatchService(provider RitoProvider, archive MatchArchiveRepository, metrics Metrics) MatchService {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	return matchService{ritoProvider: provider, archive: archive, metrics: metrics, mu: &sync.Mutex{}}
+}
+
+const (
+	// tokenCheckTTL is how long the result of the rito token check is reused, so the readiness probes do not spend the rate limit
+	tokenCheckTTL = 5 * time.Minute
+	// tokenCheckTimeout bounds the rito token check, which outlives the probe that started it
+	tokenCheckTimeout = 5 * time.Second
+)
+
+type healthService struct {
+	ritoProvider RitoProvider
+	store        Pinger
+	tokenCheck   *tokenCheck
+}
+
+// tokenCheck keeps the latest conclusive result of the rito token check and the check in flight,
+// the concurrent probes wait for the same check instead of sending one each
+type tokenCheck struct {
+	mu       sync.Mutex
+	latest   domain.DependencyCheck
+	inFlight *tokenCheckFlight
+}
+
+type tokenCheckFlight struct {
+	done  chan struct{}
+	check domain.DependencyCheck
+}
+
+type HealthService interface {
+	CheckHealth() domain.Health
+	// CheckReadiness checks the rito token, the cache, the circuit breakers and the store
+	CheckReadiness(ctx context.Context) domain.Readiness
+}
+
+// CheckHealth reports the service as degraded while any host circuit breaker is not closed
+func (h healthService) CheckHealth() domain.Health {
+	states := h.ritoProvider.CircuitBreakerStates()
+	status := "ok"
+	for _, state := range states {
+		if state != "closed" {
+			status = "degraded"
+		}
+	}
+	return domain.Health{Status: status, CircuitBreakers: states}
+}
+
+func (h healthService) CheckReadiness(ctx context.Context) domain.Readiness {
+	readiness := domain.Readiness{
+		Status: domain.ReadinessReady,
+		Checks: map[string]domain.DependencyCheck{
+			"rito_token":       h.checkToken(ctx),
+			"cache":            dependencyCheck(h.ritoProvider.CheckCache()),
+			"circuit_breakers": h.checkCircuitBreakers(),
+			"store":            dependencyCheck(h.store.Ping()),
+		},
+	}
+	for name, check := range readiness.Checks {
+		if check.Status == domain.CheckFailed {
+			readiness.Status = domain.ReadinessNotReady
+			logging.FromContext(ctx).Warn("a dependency is not ready", "dependency", name, "message", check.Message)
+		}
+	}
+	return readiness
+}
+
+// checkToken reuses the latest conclusive result, rito being unreachable says nothing about the token and is not kept.
+// The check is not tied to the probe, a probe giving up does not cancel it for the others waiting.
+func (h healthService) checkToken(ctx context.Context) domain.DependencyCheck {
+	h.tokenCheck.mu.Lock()
+	if time.Since(h.tokenCheck.latest.CheckedAt) < tokenCheckTTL {
+		defer h.tokenCheck.mu.Unlock()
+		return h.tokenCheck.latest
+	}
+	flight := h.tokenCheck.inFlight
+	if flight == nil {
+		flight = &tokenCheckFlight{done: make(chan struct{})}
+		h.tokenCheck.inFlight = flight
+		// the logger of the probe, with its request id, is kept without its cancellation
+		go h.runTokenCheck(logging.NewContext(context.Background(), logging.FromContext(ctx)), flight)
+	}
+	h.tokenCheck.mu.Unlock()
+
+	select {
+	case <-flight.done:
+		return flight.check
+	case <-ctx.Done():
+		return domain.DependencyCheck{
+			Status:    domain.CheckDegraded,
+			Message:   "the rito token check is still running",
+			CheckedAt: time.Now().UTC(),
+		}
+	}
+}
+
+func (h healthService) runTokenCheck(ctx context.Context, flight *tokenCheckFlight) {
+	ctx, cancel := context.WithTimeout(ctx, tokenCheckTimeout)
+	defer cancel()
+
+	err := h.ritoProvider.CheckToken(ctx)
+	conclusive := err == nil || errors.Is(err, ErrRitoTokenRejected)
+	flight.check = dependencyCheck(err)
+	if !conclusive {
+		flight.check.Status = domain.CheckDegraded
+	}
+
+	h.tokenCheck.mu.Lock()
+	if conclusive {
+		h.tokenCheck.latest = flight.check
+	}
+	h.tokenCheck.inFlight = nil
+	h.tokenCheck.mu.Unlock()
+	close(flight.done)
+}
+
+func (h healthService) checkCircuitBreakers() domain.DependencyCheck {
+	check := domain.DependencyCheck{Status: domain.CheckOk, Details: h.ritoProvider.CircuitBreakerStates(), CheckedAt: time.Now().UTC()}
+	for region, state := range check.Details {
+		if state != "closed" {
+			check.Status = domain.CheckDegraded
+			check.Message = fmt.Sprintf("the circuit breaker of %s is %s", region, state)
+		}
+	}
+	return check
+}
+
+func dependencyCheck(err error) domain.DependencyCheck {
+	if err != nil {
+		return domain.DependencyCheck{Status: domain.CheckFailed, Message: err.Error(), CheckedAt: time.Now().UTC()}
+	}
+	return domain.DependencyCheck{Status: domain.CheckOk, CheckedAt: time.Now().UTC()}
 }
 
-func NewMatchService(provider RitoProvider) MatchService {
-	return matchService{ritoProvider: provider, mu: &sync.Mutex{}}
+// NewHealthService checks the readiness of the provider and the store
+func NewHealthService(provider RitoProvider, store Pinger) HealthService {
+	return healthService{ritoProvider: provider, store: store, tokenCheck: &tokenCheck{}}
 }
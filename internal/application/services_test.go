@@ -0,0 +1,188 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFindFeaturedMatchesByRegion(t *testing.T) {
+	t.Run("Test the featured games are enriched with a bounded number of requests in flight", func(t *testing.T) {
+		featuredDTO := &providers.FeaturedGamesDTO{ClientRefreshInterval: 300}
+		for game := int64(1); game <= 5; game++ {
+			matchDTO := providers.MatchDTO{GameId: game}
+			for i := 0; i < 10; i++ {
+				matchDTO.Participants = append(matchDTO.Participants, providers.ParticipantDTO{
+					TeamId:       100,
+					SummonerName: fmt.Sprintf("summoner_%d_%d", game, i),
+				})
+			}
+			featuredDTO.GameList = append(featuredDTO.GameList, matchDTO)
+		}
+
+		var mu sync.Mutex
+		inFlight, maxInFlight := 0, 0
+		provider := ritoProviderMock{
+			findFeaturedMatches: func() (*providers.FeaturedGamesDTO, error) {
+				return featuredDTO, nil
+			},
+			findSummonerByName: func(region string, name string) (*providers.SummonerDTO, error) {
+				mu.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				mu.Unlock()
+				time.Sleep(5 * time.Millisecond)
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return &providers.SummonerDTO{Id: name + "_id", Name: name}, nil
+			},
+		}
+
+		featured, err := NewMatchService(provider, nil, nil).FindFeaturedMatchesByRegion(context.Background(), "test_region")
+		assert.Nil(t, err)
+		assert.EqualValues(t, 300, featured.RefreshIntervalSeconds)
+		assert.Len(t, featured.Matches, 5)
+		for i, match := range featured.Matches {
+			assert.EqualValues(t, i+1, match.GameId)
+			assert.Len(t, match.Summoners, 10)
+		}
+		assert.LessOrEqual(t, maxInFlight, enrichmentConcurrency)
+	})
+}
+
+func TestEnrichMatch(t *testing.T) {
+	t.Run("Test the participants waiting for a slot are not looked up once the request is cancelled", func(t *testing.T) {
+		var lookups int32
+		provider := ritoProviderMock{findSummonerByName: func(region string, name string) (*providers.SummonerDTO, error) {
+			atomic.AddInt32(&lookups, 1)
+			return &providers.SummonerDTO{Id: name + "_id", Name: name}, nil
+		}}
+		matchDTO := &providers.MatchDTO{GameId: 1}
+		for i := 0; i < 10; i++ {
+			matchDTO.Participants = append(matchDTO.Participants, providers.ParticipantDTO{SummonerName: fmt.Sprintf("name_%d", i)})
+		}
+		// every slot is held by another match
+		slots := make(chan struct{}, 1)
+		slots <- struct{}{}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		match := enrichMatch(ctx, provider, "test_region", matchDTO, slots)
+		assert.Empty(t, match.Summoners)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&lookups))
+	})
+}
+
+func TestCheckReadiness(t *testing.T) {
+	t.Run("Test the instance is ready with a degraded circuit breaker", func(t *testing.T) {
+		provider := ritoProviderMock{circuitBreakerStates: map[string]string{"euw1": "closed", "na1": "open"}}
+
+		readiness := NewHealthService(provider, pingerMock{}).CheckReadiness(context.Background())
+		assert.Equal(t, domain.ReadinessReady, readiness.Status)
+		assert.Equal(t, domain.CheckOk, readiness.Checks["rito_token"].Status)
+		assert.Equal(t, domain.CheckOk, readiness.Checks["cache"].Status)
+		assert.Equal(t, domain.CheckOk, readiness.Checks["store"].Status)
+		assert.Equal(t, domain.CheckDegraded, readiness.Checks["circuit_breakers"].Status)
+		assert.Equal(t, provider.circuitBreakerStates, readiness.Checks["circuit_breakers"].Details)
+	})
+	t.Run("Test the instance is not ready while the store is down", func(t *testing.T) {
+		readiness := NewHealthService(ritoProviderMock{}, pingerMock{err: fmt.Errorf("database is locked")}).
+			CheckReadiness(context.Background())
+		assert.Equal(t, domain.ReadinessNotReady, readiness.Status)
+		assert.Equal(t, domain.DependencyCheck{
+			Status:    domain.CheckFailed,
+			Message:   "database is locked",
+			CheckedAt: readiness.Checks["store"].CheckedAt,
+		}, readiness.Checks["store"])
+	})
+	t.Run("Test a rejected token is checked once while the result is fresh", func(t *testing.T) {
+		checks := 0
+		service := NewHealthService(ritoProviderMock{checkToken: func(ctx context.Context) error {
+			checks++
+			return ErrRitoTokenRejected
+		}}, pingerMock{})
+
+		for i := 0; i < 3; i++ {
+			readiness := service.CheckReadiness(context.Background())
+			assert.Equal(t, domain.ReadinessNotReady, readiness.Status)
+			assert.Equal(t, domain.CheckFailed, readiness.Checks["rito_token"].Status)
+			assert.Equal(t, "rito token can be expired", readiness.Checks["rito_token"].Message)
+		}
+		assert.Equal(t, 1, checks)
+	})
+	t.Run("Test an unreachable rito degrades the token check without keeping it", func(t *testing.T) {
+		checks := 0
+		service := NewHealthService(ritoProviderMock{checkToken: func(ctx context.Context) error {
+			checks++
+			return UpstreamUnavailableError{Region: "euw1"}
+		}}, pingerMock{})
+
+		for i := 0; i < 2; i++ {
+			readiness := service.CheckReadiness(context.Background())
+			assert.Equal(t, domain.ReadinessReady, readiness.Status)
+			assert.Equal(t, domain.CheckDegraded, readiness.Checks["rito_token"].Status)
+		}
+		assert.Equal(t, 2, checks)
+	})
+	t.Run("Test the concurrent probes share the token check in flight", func(t *testing.T) {
+		var checks int32
+		release := make(chan struct{})
+		service := NewHealthService(ritoProviderMock{checkToken: func(ctx context.Context) error {
+			atomic.AddInt32(&checks, 1)
+			<-release
+			return nil
+		}}, pingerMock{})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				readiness := service.CheckReadiness(context.Background())
+				assert.Equal(t, domain.CheckOk, readiness.Checks["rito_token"].Status)
+			}()
+		}
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+	})
+	t.Run("Test a probe giving up does not cancel the token check", func(t *testing.T) {
+		checked := make(chan error, 1)
+		release := make(chan struct{})
+		service := NewHealthService(ritoProviderMock{checkToken: func(ctx context.Context) error {
+			<-release
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			checked <- ctx.Err()
+			return nil
+		}}, pingerMock{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		readiness := service.CheckReadiness(ctx)
+		assert.Equal(t, domain.CheckDegraded, readiness.Checks["rito_token"].Status)
+		assert.Equal(t, "the rito token check is still running", readiness.Checks["rito_token"].Message)
+		close(release)
+
+		assert.Nil(t, <-checked)
+	})
+}
+
+type pingerMock struct {
+	err error
+}
+
+func (p pingerMock) Ping() error {
+	return p.err
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"time"
+)
+
+// statusLocale is the locale of the titles and updates, falling back to the first translation
+const statusLocale = "en_US"
+
+type StatusService interface {
+	FindPlatformStatus(ctx context.Context, region string) (*domain.PlatformStatus, error)
+	// FindActiveNotices returns the ongoing incidents and maintenances of the region, none when the status is unavailable
+	FindActiveNotices(ctx context.Context, region string) []domain.PlatformNotice
+}
+
+type statusService struct {
+	ritoProvider RitoProvider
+}
+
+func (s statusService) FindPlatformStatus(ctx context.Context, region string) (*domain.PlatformStatus, error) {
+	platformDTO, err := s.ritoProvider.FindPlatformStatusByRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.PlatformStatus{
+		Region:       region,
+		Name:         platformDTO.Name,
+		Incidents:    make([]domain.PlatformNotice, 0, len(platformDTO.Incidents)),
+		Maintenances: make([]domain.PlatformNotice, 0, len(platformDTO.Maintenances)),
+	}
+	for _, incidentDTO := range platformDTO.Incidents {
+		status.Incidents = append(status.Incidents, buildNotice(incidentDTO))
+	}
+	for _, maintenanceDTO := range platformDTO.Maintenances {
+		status.Maintenances = append(status.Maintenances, buildNotice(maintenanceDTO))
+	}
+	return status, nil
+}
+
+func (s statusService) FindActiveNotices(ctx context.Context, region string) []domain.PlatformNotice {
+	status, err := s.FindPlatformStatus(ctx, region)
+	if err != nil {
+		logging.FromContext(ctx).Warn("could not find the platform status", "region", region, "err", err)
+		return nil
+	}
+	return status.ActiveNotices()
+}
+
+func buildNotice(statusDTO providers.StatusDTO) domain.PlatformNotice {
+	notice := domain.PlatformNotice{
+		Id:        statusDTO.Id,
+		Severity:  statusDTO.IncidentSeverity,
+		Status:    statusDTO.MaintenanceStatus,
+		Title:     translate(statusDTO.Titles),
+		Updates:   make([]string, 0, len(statusDTO.Updates)),
+		Platforms: statusDTO.Platforms,
+	}
+	if createdAt, err := time.Parse(time.RFC3339, statusDTO.CreatedAt); err == nil {
+		notice.CreatedAt = createdAt.UTC()
+	}
+	for _, updateDTO := range statusDTO.Updates {
+		if updateDTO.Publish {
+			notice.Updates = append(notice.Updates, translate(updateDTO.Translations))
+		}
+	}
+	return notice
+}
+
+func translate(contents []providers.ContentDTO) string {
+	for _, content := range contents {
+		if content.Locale == statusLocale {
+			return content.Content
+		}
+	}
+	if len(contents) > 0 {
+		return contents[0].Content
+	}
+	return ""
+}
+
+func NewStatusService(provider RitoProvider) StatusService {
+	return statusService{ritoProvider: provider}
+}
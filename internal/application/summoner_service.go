@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+)
+
+type SummonerService interface {
+	FindSummonerByRegionAndName(ctx context.Context, region string, summonerName string) (*domain.SummonerProfile, error)
+	FindLeaguesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]domain.League, error)
+}
+
+type summonerService struct {
+	ritoProvider RitoProvider
+}
+
+func (s summonerService) FindSummonerByRegionAndName(ctx context.Context, region string, summonerName string) (*domain.SummonerProfile, error) {
+	summonerDTO, err := s.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, err
+	}
+	leagues, err := s.FindLeaguesByRegionAndSummonerId(ctx, region, summonerDTO.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SummonerProfile{
+		Id:      summonerDTO.Id,
+		Puuid:   summonerDTO.Puuid,
+		Name:    summonerDTO.Name,
+		Level:   summonerDTO.Level,
+		Leagues: leagues,
+	}, nil
+}
+
+func (s summonerService) FindLeaguesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]domain.League, error) {
+	leaguesDTO, err := s.ritoProvider.FindLeaguesByRegionAndSummonerId(ctx, region, summonerId)
+	if err != nil {
+		return nil, err
+	}
+	return buildLeagues(leaguesDTO), nil
+}
+
+func buildLeagues(leaguesDTO []providers.LeagueInfoDTO) []domain.League {
+	leagues := make([]domain.League, 0, len(leaguesDTO))
+	for _, leagueDTO := range leaguesDTO {
+		leagues = append(leagues, domain.NewLeague(
+			leagueDTO.QueueType,
+			leagueDTO.Tier,
+			leagueDTO.Rank,
+			leagueDTO.LeaguePoints,
+			leagueDTO.Wins,
+			leagueDTO.Losses,
+		))
+	}
+	return leagues
+}
+
+func NewSummonerService(provider RitoProvider) SummonerService {
+	return summonerService{ritoProvider: provider}
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"time"
+)
+
+type WatchlistService interface {
+	Watch(ctx context.Context, region string, summonerName string) (*domain.WatchedSummoner, error)
+	Unwatch(region string, summonerId string) error
+	FindWatchedSummoners() ([]domain.WatchedSummoner, error)
+	FindDeliveries(limit int) ([]domain.WebhookDelivery, error)
+}
+
+type watchlistService struct {
+	ritoProvider       RitoProvider
+	watchlist          WatchlistRepository
+	deliveryRepository DeliveryRepository
+}
+
+func (w watchlistService) Watch(ctx context.Context, region string, summonerName string) (*domain.WatchedSummoner, error) {
+	summonerDTO, err := w.ritoProvider.FindSummonerByRegionAndName(ctx, region, summonerName)
+	if err != nil {
+		return nil, err
+	}
+	watched := domain.WatchedSummoner{
+		Region:       region,
+		SummonerId:   summonerDTO.Id,
+		SummonerName: summonerDTO.Name,
+		CreatedAt:    time.Now().UTC(),
+	}
+	if err = w.watchlist.SaveWatchedSummoner(watched); err != nil {
+		return nil, err
+	}
+	return &watched, nil
+}
+
+func (w watchlistService) Unwatch(region string, summonerId string) error {
+	return w.watchlist.DeleteWatchedSummoner(region, summonerId)
+}
+
+func (w watchlistService) FindWatchedSummoners() ([]domain.WatchedSummoner, error) {
+	return w.watchlist.FindWatchedSummoners()
+}
+
+func (w watchlistService) FindDeliveries(limit int) ([]domain.WebhookDelivery, error) {
+	return w.deliveryRepository.FindDeliveries(limit)
+}
+
+func NewWatchlistService(provider RitoProvider, watchlist WatchlistRepository, deliveries DeliveryRepository) WatchlistService {
+	return watchlistService{ritoProvider: provider, watchlist: watchlist, deliveryRepository: deliveries}
+}
+
+// enrichmentRequestsPerParticipant are the summoner and leagues lookups enriching each participant of a started game
+const enrichmentRequestsPerParticipant = 2
+
+// WatchlistScheduler polls the active game of the watched summoners one at a time,
+// spacing the rito requests so they stay within the budget per minute, the enrichment of the started games included.
+type WatchlistScheduler struct {
+	ritoProvider RitoProvider
+	watchlist    WatchlistRepository
+	notifier     Notifier
+	task         *periodicTask
+	// pending are the summoners left to poll in the current round, only used by the task
+	pending []domain.WatchedSummoner
+	// owed are the requests spent over the one of the latest poll, paid back by skipping as many ticks
+	owed int
+}
+
+func NewWatchlistScheduler(provider RitoProvider, watchlist WatchlistRepository, notifier Notifier, budgetPerMinute int) *WatchlistScheduler {
+	if budgetPerMinute <= 0 {
+		budgetPerMinute = 1
+	}
+	scheduler := &WatchlistScheduler{
+		ritoProvider: provider,
+		watchlist:    watchlist,
+		notifier:     notifier,
+	}
+	scheduler.task = newPeriodicTask("watchlist", time.Minute/time.Duration(budgetPerMinute), scheduler.tick)
+	return scheduler
+}
+
+// Start polls in background until Stop is called
+func (s *WatchlistScheduler) Start() {
+	s.task.Start()
+}
+
+// Stop waits for the poll in progress to finish
+func (s *WatchlistScheduler) Stop() {
+	s.task.Stop()
+}
+
+// tick spends a request of the budget, either paying back the latest poll or polling the next summoner
+func (s *WatchlistScheduler) tick(ctx context.Context) {
+	if s.owed > 0 {
+		s.owed--
+		return
+	}
+	if len(s.pending) == 0 {
+		watched, err := s.watchlist.FindWatchedSummoners()
+		if err != nil {
+			logging.FromContext(ctx).Error("could not load the watchlist", "err", err)
+			return
+		}
+		s.pending = watched
+	}
+	if len(s.pending) == 0 {
+		return
+	}
+	s.owed = s.Poll(ctx, s.pending[0]) - 1
+	s.pending = s.pending[1:]
+}
+
+// Poll checks the active game of the summoner, notifying and storing any game start or end.
+// It returns the rito requests it spent, the participants of a started game are looked up for the notification.
+func (s *WatchlistScheduler) Poll(ctx context.Context, watched domain.WatchedSummoner) int {
+	requests := 1
+	matchDTO, err := s.ritoProvider.FindMatchBySummonerId(ctx, watched.Region, watched.SummonerId)
+	if err != nil && !errors.Is(err, ErrMatchNotFound) {
+		logging.FromContext(ctx).Warn("could not poll the active game", "summoner_id", watched.SummonerId, "err", err)
+		return requests
+	}
+
+	event := domain.WatchEvent{
+		Region:       watched.Region,
+		SummonerId:   watched.SummonerId,
+		SummonerName: watched.SummonerName,
+		OccurredAt:   time.Now().UTC(),
+	}
+	switch {
+	case err != nil && watched.GameId != 0:
+		event.Type = domain.LiveGameEnded
+		event.GameId = watched.GameId
+		watched.GameId = 0
+	case err == nil && watched.GameId != matchDTO.GameId:
+		event.Type = domain.LiveGameStarted
+		event.GameId = matchDTO.GameId
+		event.Match = buildMatch(ctx, s.ritoProvider, watched.Region, matchDTO)
+		requests += enrichmentRequestsPerParticipant * len(matchDTO.Participants)
+		watched.GameId = matchDTO.GameId
+	default:
+		return requests
+	}
+
+	if err = s.watchlist.UpdateWatchedGame(watched.Region, watched.SummonerId, watched.GameId); err != nil {
+		logging.FromContext(ctx).Error("could not save the watched summoner", "summoner_id", watched.SummonerId, "err", err)
+		return requests
+	}
+	s.notifier.Notify(event)
+	return requests
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWatchlistSchedulerPoll(t *testing.T) {
+	tests := []struct {
+		name           string
+		watchedGameId  int64
+		activeGameId   int64
+		expectedEvents []string
+		expectedGameId int64
+	}{
+		{"Test poll notifies when the summoner enters a game", 0, 1, []string{domain.LiveGameStarted}, 1},
+		{"Test poll notifies when the summoner finishes the game", 1, 0, []string{domain.LiveGameEnded}, 0},
+		{"Test poll does not notify while the game goes on", 1, 1, nil, 1},
+		{"Test poll does not notify while the summoner is not in game", 0, 0, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := ritoProviderMock{
+				findMatchBySummonerId: func(region string, summonerId string) (*providers.MatchDTO, error) {
+					if tt.activeGameId == 0 {
+						return nil, ErrMatchNotFound
+					}
+					return &providers.MatchDTO{GameId: tt.activeGameId}, nil
+				},
+			}
+			watchlist := &watchlistRepositoryMock{gameId: tt.watchedGameId}
+			notifier := &notifierMock{}
+			scheduler := NewWatchlistScheduler(provider, watchlist, notifier, 60)
+
+			scheduler.Poll(context.Background(), domain.WatchedSummoner{Region: "test_region", SummonerId: "test_id", GameId: tt.watchedGameId})
+
+			var events []string
+			for _, event := range notifier.events {
+				events = append(events, event.Type)
+			}
+			assert.Equal(t, tt.expectedEvents, events)
+			assert.Equal(t, tt.expectedGameId, watchlist.gameId)
+		})
+	}
+}
+
+func TestWatchlistSchedulerBudget(t *testing.T) {
+	t.Run("Test the enrichment of a started game is paid back before polling again", func(t *testing.T) {
+		polls := 0
+		provider := ritoProviderMock{
+			findMatchBySummonerId: func(region string, summonerId string) (*providers.MatchDTO, error) {
+				polls++
+				return &providers.MatchDTO{GameId: 1, Participants: []providers.ParticipantDTO{
+					{TeamId: 100, SummonerId: "first_id"},
+					{TeamId: 200, SummonerId: "second_id"},
+				}}, nil
+			},
+		}
+		watchlist := &watchlistRepositoryMock{watched: []domain.WatchedSummoner{{Region: "test_region", SummonerId: "test_id"}}}
+		scheduler := NewWatchlistScheduler(provider, watchlist, &notifierMock{}, 60)
+
+		var pollsPerTick []int
+		for i := 0; i < 6; i++ {
+			scheduler.tick(context.Background())
+			pollsPerTick = append(pollsPerTick, polls)
+		}
+		// the poll and the summoner and leagues of both participants are 5 requests, 4 ticks are skipped
+		assert.Equal(t, []int{1, 1, 1, 1, 1, 2}, pollsPerTick)
+	})
+}
+
+type watchlistRepositoryMock struct {
+	gameId  int64
+	watched []domain.WatchedSummoner
+}
+
+func (w *watchlistRepositoryMock) SaveWatchedSummoner(summoner domain.WatchedSummoner) error {
+	return nil
+}
+
+func (w *watchlistRepositoryMock) UpdateWatchedGame(region string, summonerId string, gameId int64) error {
+	w.gameId = gameId
+	return nil
+}
+
+func (w *watchlistRepositoryMock) DeleteWatchedSummoner(region string, summonerId string) error {
+	return nil
+}
+
+func (w *watchlistRepositoryMock) FindWatchedSummoners() ([]domain.WatchedSummoner, error) {
+	return w.watched, nil
+}
+
+type notifierMock struct {
+	events []domain.WatchEvent
+}
+
+func (n *notifierMock) Notify(event domain.WatchEvent) {
+	n.events = append(n.events, event)
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// APIClient is a consumer of the api, its requests are limited per minute
+type APIClient struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// Key authenticates the client, it is only answered when the client is issued
+	Key               string    `json:"key,omitempty"`
+	RequestsPerMinute int       `json:"requests_per_minute"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// WithoutKey is the client as listed to the admins
+func (c APIClient) WithoutKey() APIClient {
+	c.Key = ""
+	return c
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// clashPositions in the order the team is shown, from top lane to support
+var clashPositions = map[string]int{
+	"TOP":        0,
+	"JUNGLE":     1,
+	"MIDDLE":     2,
+	"BOTTOM":     3,
+	"UTILITY":    4,
+	"FILL":       5,
+	"UNSELECTED": 6,
+}
+
+type ClashTeam struct {
+	Id           string        `json:"id"`
+	TournamentId int64         `json:"tournament_id"`
+	Name         string        `json:"name"`
+	Abbreviation string        `json:"abbreviation"`
+	Tier         int           `json:"tier"`
+	Players      []ClashPlayer `json:"players"`
+}
+
+type ClashPlayer struct {
+	SummonerId   string            `json:"summoner_id"`
+	SummonerName string            `json:"summoner_name"`
+	Position     string            `json:"position"`
+	Role         string            `json:"role"`
+	Leagues      []League          `json:"leagues"`
+	TopChampions []ChampionMastery `json:"top_champions"`
+}
+
+type ChampionMastery struct {
+	ChampionId int64 `json:"champion_id"`
+	Level      int   `json:"level"`
+	Points     int   `json:"points"`
+}
+
+type ClashTournament struct {
+	Id            int64        `json:"id"`
+	Name          string       `json:"name"`
+	SecondaryName string       `json:"secondary_name"`
+	Schedule      []ClashPhase `json:"schedule"`
+}
+
+type ClashPhase struct {
+	Id               int64     `json:"id"`
+	RegistrationTime time.Time `json:"registration_time"`
+	StartTime        time.Time `json:"start_time"`
+	Cancelled        bool      `json:"cancelled"`
+}
+
+// SortClashPlayers orders the players by position, unknown positions last
+func SortClashPlayers(players []ClashPlayer) {
+	order := func(position string) int {
+		if index, exists := clashPositions[position]; exists {
+			return index
+		}
+		return len(clashPositions)
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		return order(players[i].Position) < order(players[j].Position)
+	})
+}
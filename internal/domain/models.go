@@ -1,43 +1,151 @@
 package domain
 
+import "time"
+
 type League struct {
-	QueueType string  `json:"queue_type"`
-	Tier      string  `json:"tier"` //"MASTER"
-	Rank      string  `json:"rank"` //"I"
-	Wins      int     `json:"wins"`
-	Losses    int     `json:"losses"`
-	WinRate   float32 `json:"win_rate"`
+	QueueType    string  `json:"queue_type"`
+	Tier         string  `json:"tier"` //"MASTER"
+	Rank         string  `json:"rank"` //"I"
+	LeaguePoints int     `json:"league_points"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	WinRate      float32 `json:"win_rate"`
 }
 
 type Summoner struct {
+	Id         string   `json:"id"`
+	Name       string   `json:"name"`
+	Level      int      `json:"level"`
+	TeamId     int64    `json:"team_id"`
+	ChampionId int64    `json:"champion_id"`
+	Leagues    []League `json:"leagues"`
+}
+
+// SummonerProfile is a summoner outside of a game
+type SummonerProfile struct {
 	Id      string   `json:"id"`
+	Puuid   string   `json:"puuid"`
 	Name    string   `json:"name"`
 	Level   int      `json:"level"`
-	TeamId  int64    `json:"team_id"`
 	Leagues []League `json:"leagues"`
 }
 
 type Match struct {
-	Summoners []Summoner `json:"summoners"`
+	GameId  int64 `json:"game_id"`
+	QueueId int64 `json:"queue_id"`
+	// GameStartTime epoch in milliseconds, zero while the game is loading
+	GameStartTime int64      `json:"game_start_time"`
+	Summoners     []Summoner `json:"summoners"`
+}
+
+// FeaturedMatches are the games rito showcases in a region
+type FeaturedMatches struct {
+	Region string `json:"region"`
+	// RefreshIntervalSeconds is how long to wait before asking for the featured games again
+	RefreshIntervalSeconds int64   `json:"refresh_interval_seconds"`
+	Matches                []Match `json:"matches"`
+}
+
+const (
+	LiveGameStarted = "game_started"
+	LiveGameUpdated = "game_update"
+	LiveGameEnded   = "game_ended"
+)
+
+type LiveGameEvent struct {
+	Type           string `json:"type"`
+	GameId         int64  `json:"game_id"`
+	ElapsedSeconds int64  `json:"elapsed_seconds"`
+	// Match is only sent when the game starts
+	Match *Match `json:"match,omitempty"`
+}
+
+// ArchivedGame is the first snapshot of a game taken by a lookup, so the ranks are the ones at game time
+type ArchivedGame struct {
+	Region     string    `json:"region"`
+	ArchivedAt time.Time `json:"archived_at"`
+	Match
+}
+
+type WatchedSummoner struct {
+	Region       string    `json:"region"`
+	SummonerId   string    `json:"summoner_id"`
+	SummonerName string    `json:"summoner_name"`
+	CreatedAt    time.Time `json:"created_at"`
+	// GameId of the game in progress, zero when the summoner is not in game
+	GameId int64 `json:"game_id"`
+}
+
+type WatchEvent struct {
+	Type         string    `json:"type"`
+	Region       string    `json:"region"`
+	SummonerId   string    `json:"summoner_id"`
+	SummonerName string    `json:"summoner_name"`
+	GameId       int64     `json:"game_id"`
+	OccurredAt   time.Time `json:"occurred_at"`
+	// Match is only sent when the game starts
+	Match *Match `json:"match,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Id         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventType  string    `json:"event_type"`
+	SummonerId string    `json:"summoner_id"`
+	GameId     int64     `json:"game_id"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Health struct {
+	Status          string            `json:"status"`
+	CircuitBreakers map[string]string `json:"circuit_breakers"`
+}
+
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not_ready"
+
+	CheckOk       = "ok"
+	CheckDegraded = "degraded"
+	CheckFailed   = "failed"
+)
+
+// Readiness reports the dependencies the api needs to answer, it is not ready while any of them failed
+type Readiness struct {
+	Status string                     `json:"status"`
+	Checks map[string]DependencyCheck `json:"checks"`
+}
+
+// DependencyCheck is the result of checking a dependency, a degraded one still allows to answer
+type DependencyCheck struct {
+	Status    string            `json:"status"`
+	Message   string            `json:"message,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CheckedAt time.Time         `json:"checked_at"`
 }
 
-func NewLeague(queueType string, tier string, rank string, wins int, losses int) League {
+func NewLeague(queueType string, tier string, rank string, leaguePoints int, wins int, losses int) League {
 	return League{
-		QueueType: queueType,
-		Tier:      tier,
-		Rank:      rank,
-		Wins:      wins,
-		Losses:    losses,
-		WinRate:   float32(wins) / float32(wins+losses),
+		QueueType:    queueType,
+		Tier:         tier,
+		Rank:         rank,
+		LeaguePoints: leaguePoints,
+		Wins:         wins,
+		Losses:       losses,
+		WinRate:      float32(wins) / float32(wins+losses),
 	}
 }
 
-func NewSummoner(id string, name string, level int, teamId int64, leagues []League) Summoner {
+func NewSummoner(id string, name string, level int, teamId int64, championId int64, leagues []League) Summoner {
 	return Summoner{
-		Id:      id,
-		Name:    name,
-		Level:   level,
-		TeamId:  teamId,
-		Leagues: leagues,
+		Id:         id,
+		Name:       name,
+		Level:      level,
+		TeamId:     teamId,
+		ChampionId: championId,
+		Leagues:    leagues,
 	}
 }
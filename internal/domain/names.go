@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	minNameLength = 3
+	maxNameLength = 16
+	minTagLength  = 3
+	maxTagLength  = 5
+)
+
+// ValidateSummonerName checks the rito rules of a summoner name, or of a riot id when it has a tag (name#tag)
+func ValidateSummonerName(name string) error {
+	gameName, tag, hasTag := name, "", false
+	if separator := strings.LastIndex(name, "#"); separator >= 0 {
+		gameName, tag, hasTag = name[:separator], name[separator+1:], true
+	}
+
+	length := utf8.RuneCountInString(strings.TrimSpace(gameName))
+	if length < minNameLength || length > maxNameLength {
+		return fmt.Errorf("should have between %d and %d characters", minNameLength, maxNameLength)
+	}
+	for _, r := range gameName {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '_' && r != '.' {
+			return fmt.Errorf("should only have letters, numbers, spaces, underscores and periods")
+		}
+	}
+	if !hasTag {
+		return nil
+	}
+
+	length = utf8.RuneCountInString(tag)
+	if length < minTagLength || length > maxTagLength {
+		return fmt.Errorf("should have a tag between %d and %d characters", minTagLength, maxTagLength)
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return fmt.Errorf("should have a tag with only letters and numbers")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidateSummonerName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"xNibe", true},
+		{"Broken Blade", true},
+		{"Señor.Ñandú_1", true},
+		{"Faker#KR1", true},
+		{"ab", false},
+		{"a name way too long for rito", false},
+		{"drop;table", false},
+		{"../../admin", false},
+		{"Faker#", false},
+		{"Faker#KR-1", false},
+		{"Faker#LONGTAG", false},
+	}
+
+	for _, tt := range tests {
+		t.Run("Test validate the summoner name "+tt.name, func(t *testing.T) {
+			err := ValidateSummonerName(tt.name)
+			assert.Equal(t, tt.valid, err == nil, err)
+		})
+	}
+}
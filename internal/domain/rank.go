@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	SoloQueueType = "RANKED_SOLO_5x5"
+	FlexQueueType = "RANKED_FLEX_SR"
+
+	RankPromotion = "promotion"
+	RankDemotion  = "demotion"
+)
+
+// tiers from the lowest, apex tiers (master and above) have no divisions
+var tiers = []string{"IRON", "BRONZE", "SILVER", "GOLD", "PLATINUM", "EMERALD", "DIAMOND", "MASTER", "GRANDMASTER", "CHALLENGER"}
+
+var divisions = map[string]int{"IV": 0, "III": 1, "II": 2, "I": 3}
+
+const (
+	pointsPerDivision = 100
+	firstApexTier     = "MASTER"
+)
+
+// RankScore turns a standing into a number that can be compared and subtracted across tiers and divisions.
+// Each division is worth 100 league points, apex tiers share the score of master so their LP keep adding up.
+// Unknown tiers score -1.
+func RankScore(tier string, rank string, leaguePoints int) int {
+	tierIndex := tierIndexOf(tier)
+	if tierIndex < 0 {
+		return -1
+	}
+	if tierIndex >= tierIndexOf(firstApexTier) {
+		return tierIndexOf(firstApexTier)*len(divisions)*pointsPerDivision + leaguePoints
+	}
+	return (tierIndex*len(divisions)+divisions[rank])*pointsPerDivision + leaguePoints
+}
+
+// CompareRank returns a positive number when a is ranked above b, negative when below and zero when even.
+// Unlike RankScore it also orders the apex tiers among themselves.
+func CompareRank(a League, b League) int {
+	tierDiff := tierIndexOf(a.Tier) - tierIndexOf(b.Tier)
+	if tierDiff != 0 {
+		return tierDiff
+	}
+	return RankScore(a.Tier, a.Rank, a.LeaguePoints) - RankScore(b.Tier, b.Rank, b.LeaguePoints)
+}
+
+// RankChange tells whether moving between the standings was a promotion, a demotion or none (empty)
+func RankChange(from League, to League) string {
+	fromStep := tierIndexOf(from.Tier)*len(divisions) + divisions[from.Rank]
+	toStep := tierIndexOf(to.Tier)*len(divisions) + divisions[to.Rank]
+	switch {
+	case toStep > fromStep:
+		return RankPromotion
+	case toStep < fromStep:
+		return RankDemotion
+	default:
+		return ""
+	}
+}
+
+func tierIndexOf(tier string) int {
+	for i, name := range tiers {
+		if name == tier {
+			return i
+		}
+	}
+	return -1
+}
+
+type TrackedSummoner struct {
+	Region       string    `json:"region"`
+	SummonerId   string    `json:"summoner_id"`
+	SummonerName string    `json:"summoner_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LeagueSnapshot is the standing of a summoner in a queue at a given time
+type LeagueSnapshot struct {
+	Region     string `json:"region"`
+	SummonerId string `json:"summoner_id"`
+	League
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// RankHistoryPoint is a snapshot with the changes since the previous one
+type RankHistoryPoint struct {
+	LeagueSnapshot
+	Score int `json:"score"`
+	// LPChange is the score difference, so it also accounts for the LP lost or won across divisions
+	LPChange int    `json:"lp_change"`
+	Change   string `json:"change,omitempty"`
+}
+
+// NewRankHistory derives the changes between the snapshots, which are expected to be ordered by time
+func NewRankHistory(snapshots []LeagueSnapshot) []RankHistoryPoint {
+	history := make([]RankHistoryPoint, 0, len(snapshots))
+	for i, snapshot := range snapshots {
+		point := RankHistoryPoint{
+			LeagueSnapshot: snapshot,
+			Score:          RankScore(snapshot.Tier, snapshot.Rank, snapshot.LeaguePoints),
+		}
+		if i > 0 {
+			previous := history[i-1]
+			point.LPChange = point.Score - previous.Score
+			point.Change = RankChange(previous.League, snapshot.League)
+		}
+		history = append(history, point)
+	}
+	return history
+}
+
+type Group struct {
+	Id        int64         `json:"id"`
+	Name      string        `json:"name"`
+	CreatedAt time.Time     `json:"created_at"`
+	Members   []GroupMember `json:"members"`
+}
+
+type GroupMember struct {
+	Region       string `json:"region"`
+	SummonerId   string `json:"summoner_id"`
+	SummonerName string `json:"summoner_name"`
+}
+
+type LeaderboardEntry struct {
+	Position int `json:"position"`
+	GroupMember
+	// SoloQueue is nil while the member is unranked
+	SoloQueue   *League `json:"solo_queue"`
+	GamesPlayed int     `json:"games_played"`
+	WinRate     float32 `json:"win_rate"`
+	// LPChange is the score difference against the oldest snapshot of the period
+	LPChange int `json:"lp_change"`
+}
+
+type Leaderboard struct {
+	GroupId     int64              `json:"group_id"`
+	Name        string             `json:"name"`
+	RefreshedAt time.Time          `json:"refreshed_at"`
+	Entries     []LeaderboardEntry `json:"entries"`
+}
+
+// SortLeaderboard orders the entries by solo queue standing, unranked members last, and sets their positions
+func SortLeaderboard(entries []LeaderboardEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].SoloQueue, entries[j].SoloQueue
+		switch {
+		case a == nil && b == nil:
+			return entries[i].SummonerName < entries[j].SummonerName
+		case a == nil || b == nil:
+			return b == nil
+		default:
+			return CompareRank(*a, *b) > 0
+		}
+	})
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+}
+
+type LadderEntry struct {
+	// Position in the apex ladder, rito does not rank the entries of the other tiers
+	Position     int    `json:"position,omitempty"`
+	SummonerId   string `json:"summoner_id"`
+	SummonerName string `json:"summoner_name"`
+	League
+}
+
+type Ladder struct {
+	Region   string        `json:"region"`
+	Queue    string        `json:"queue"`
+	Tier     string        `json:"tier"`
+	Division string        `json:"division,omitempty"`
+	Page     int           `json:"page"`
+	Total    int           `json:"total,omitempty"`
+	Entries  []LadderEntry `json:"entries"`
+}
+
+// LadderStanding tells where a summoner is relative to the top of the ladder
+type LadderStanding struct {
+	SummonerId string  `json:"summoner_id"`
+	League     *League `json:"league"`
+	// Position in the apex ladder, zero when the summoner is not an apex player
+	Position    int `json:"position"`
+	ApexPlayers int `json:"apex_players"`
+	// PointsToApex is the LP left to reach master, zero for apex players
+	PointsToApex int `json:"points_to_apex"`
+}
+
+// IsApexTier tells whether the tier has a single ladder without divisions
+func IsApexTier(tier string) bool {
+	return tierIndexOf(tier) >= tierIndexOf(firstApexTier)
+}
+
+// IsValidTier tells whether the tier and division exist, apex tiers only have division I
+func IsValidTier(tier string, division string) bool {
+	if tierIndexOf(tier) < 0 {
+		return false
+	}
+	if IsApexTier(tier) {
+		return division == "I"
+	}
+	_, exists := divisions[division]
+	return exists
+}
+
+// PointsToApex returns the LP left to reach master
+func PointsToApex(league League) int {
+	points := RankScore(firstApexTier, "I", 0) - RankScore(league.Tier, league.Rank, league.LeaguePoints)
+	if points < 0 {
+		return 0
+	}
+	return points
+}
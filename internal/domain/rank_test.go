@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewRankHistory(t *testing.T) {
+	t.Run("Test rank history derives lp changes, promotions and demotions", func(t *testing.T) {
+		snapshots := []LeagueSnapshot{
+			{League: NewLeague(SoloQueueType, "GOLD", "I", 90, 10, 10)},
+			{League: NewLeague(SoloQueueType, "PLATINUM", "IV", 5, 11, 10)},
+			{League: NewLeague(SoloQueueType, "PLATINUM", "IV", 0, 11, 11)},
+			{League: NewLeague(SoloQueueType, "GOLD", "I", 75, 11, 12)},
+		}
+
+		history := NewRankHistory(snapshots)
+
+		var changes []int
+		var kinds []string
+		for _, point := range history {
+			changes = append(changes, point.LPChange)
+			kinds = append(kinds, point.Change)
+		}
+		assert.Equal(t, []int{0, 15, -5, -25}, changes)
+		assert.Equal(t, []string{"", RankPromotion, "", RankDemotion}, kinds)
+	})
+}
+
+func TestCompareRank(t *testing.T) {
+	t.Run("Test apex tiers are ordered above master", func(t *testing.T) {
+		master := NewLeague(SoloQueueType, "MASTER", "I", 400, 1, 1)
+		challenger := NewLeague(SoloQueueType, "CHALLENGER", "I", 10, 1, 1)
+		diamond := NewLeague(SoloQueueType, "DIAMOND", "I", 99, 1, 1)
+
+		assert.True(t, CompareRank(challenger, master) > 0)
+		assert.True(t, CompareRank(master, diamond) > 0)
+		assert.True(t, CompareRank(diamond, diamond) == 0)
+	})
+	t.Run("Test emerald is ordered between platinum and diamond", func(t *testing.T) {
+		iron := NewLeague(SoloQueueType, "IRON", "IV", 0, 1, 1)
+		platinum := NewLeague(SoloQueueType, "PLATINUM", "I", 99, 1, 1)
+		emerald := NewLeague(SoloQueueType, "EMERALD", "IV", 0, 1, 1)
+		diamond := NewLeague(SoloQueueType, "DIAMOND", "IV", 0, 1, 1)
+
+		assert.True(t, CompareRank(emerald, iron) > 0)
+		assert.True(t, CompareRank(emerald, platinum) > 0)
+		assert.True(t, CompareRank(diamond, emerald) > 0)
+	})
+}
+
+func TestRankScore(t *testing.T) {
+	t.Run("Test emerald scores between platinum and diamond", func(t *testing.T) {
+		assert.Equal(t, 2000, RankScore("EMERALD", "IV", 0))
+		assert.Equal(t, 1, RankScore("EMERALD", "IV", 1)-RankScore("PLATINUM", "I", 100))
+		assert.Equal(t, 20, RankScore("DIAMOND", "IV", 10)-RankScore("EMERALD", "I", 90))
+		assert.True(t, IsValidTier("EMERALD", "II"))
+		assert.False(t, IsApexTier("EMERALD"))
+	})
+	t.Run("Test the promotions across emerald", func(t *testing.T) {
+		platinum := NewLeague(SoloQueueType, "PLATINUM", "I", 100, 1, 1)
+		emerald := NewLeague(SoloQueueType, "EMERALD", "IV", 0, 1, 1)
+		diamond := NewLeague(SoloQueueType, "DIAMOND", "IV", 0, 1, 1)
+
+		assert.Equal(t, RankPromotion, RankChange(platinum, emerald))
+		assert.Equal(t, RankPromotion, RankChange(emerald, diamond))
+		assert.Equal(t, RankDemotion, RankChange(diamond, emerald))
+	})
+}
+
+func TestSortLeaderboard(t *testing.T) {
+	t.Run("Test leaderboard puts the unranked members last", func(t *testing.T) {
+		gold := NewLeague(SoloQueueType, "GOLD", "I", 10, 1, 1)
+		diamond := NewLeague(SoloQueueType, "DIAMOND", "IV", 0, 1, 1)
+		entries := []LeaderboardEntry{
+			{GroupMember: GroupMember{SummonerName: "unranked"}},
+			{GroupMember: GroupMember{SummonerName: "gold"}, SoloQueue: &gold},
+			{GroupMember: GroupMember{SummonerName: "diamond"}, SoloQueue: &diamond},
+		}
+
+		SortLeaderboard(entries)
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, fmt.Sprintf("%d %s", entry.Position, entry.SummonerName))
+		}
+		assert.Equal(t, []string{"1 diamond", "2 gold", "3 unranked"}, names)
+	})
+}
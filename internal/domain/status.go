@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// MaintenanceComplete is the status of a maintenance already over
+const MaintenanceComplete = "complete"
+
+// PlatformStatus is the incidents and maintenances rito reports for a region
+type PlatformStatus struct {
+	Region       string           `json:"region"`
+	Name         string           `json:"name"`
+	Incidents    []PlatformNotice `json:"incidents"`
+	Maintenances []PlatformNotice `json:"maintenances"`
+}
+
+// PlatformNotice is an incident or a maintenance, with its messages in english
+type PlatformNotice struct {
+	Id int64 `json:"id"`
+	// Severity of an incident, empty for maintenances
+	Severity string `json:"severity,omitempty"`
+	// Status of a maintenance, empty for incidents
+	Status    string    `json:"status,omitempty"`
+	Title     string    `json:"title"`
+	Updates   []string  `json:"updates"`
+	CreatedAt time.Time `json:"created_at"`
+	Platforms []string  `json:"platforms"`
+}
+
+// ActiveNotices returns the incidents and the maintenances not completed yet
+func (p PlatformStatus) ActiveNotices() []PlatformNotice {
+	notices := append([]PlatformNotice{}, p.Incidents...)
+	for _, maintenance := range p.Maintenances {
+		if maintenance.Status != MaintenanceComplete {
+			notices = append(notices, maintenance)
+		}
+	}
+	return notices
+}
@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	apiKeyHeader   = "X-API-Key"
+	adminKeyHeader = "X-Admin-Key"
+)
+
+// unauthorizedError is answered when the request has no valid key
+type unauthorizedError struct {
+	msg string
+}
+
+func (e unauthorizedError) Error() string {
+	return e.msg
+}
+
+// forbiddenError is answered when the key is valid but cannot be used for the request
+type forbiddenError struct {
+	msg string
+}
+
+func (e forbiddenError) Error() string {
+	return e.msg
+}
+
+// rateLimitedError is answered when the client spent its requests per minute
+type rateLimitedError struct{}
+
+func (e rateLimitedError) Error() string {
+	return "Too many requests, wait for the Retry-After seconds"
+}
+
+// APIClientRequest issues an api key
+type APIClientRequest struct {
+	Name string `json:"name"`
+	// RequestsPerMinute of the client, the configured default when zero
+	RequestsPerMinute int `json:"requests_per_minute"`
+}
+
+// authenticate rejects the requests without a valid api key and limits each client to its requests per minute,
+// telling the state of its quota in the X-RateLimit headers. Every request is allowed without an APIClientService.
+func (handler RitoHandler) authenticate(c *gin.Context) {
+	if handler.APIClientService == nil {
+		return
+	}
+	client, err := handler.APIClientService.Authenticate(c.GetHeader(apiKeyHeader))
+	if errors.Is(err, application.ErrAPIClientNotFound) {
+		abortWithError(c, unauthorizedError{msg: "A valid api key is required in the " + apiKeyHeader + " header"})
+		return
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	c.Request = c.Request.WithContext(logging.NewContext(ctx, logging.FromContext(ctx).With("client_id", client.Id)))
+
+	limit := handler.RateLimiter.take(client.Id, client.RequestsPerMinute)
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit.limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(limit.remaining))
+	c.Header("X-RateLimit-Reset", ceilSeconds(limit.reset))
+	if !limit.allowed {
+		c.Header("Retry-After", ceilSeconds(limit.retryAfter))
+		abortWithError(c, rateLimitedError{})
+	}
+}
+
+// authenticateAdmin only lets through the requests with the admin key, the admin api is disabled without one
+func (handler RitoHandler) authenticateAdmin(c *gin.Context) {
+	if handler.AdminKey == "" || handler.APIClientService == nil {
+		abortWithError(c, forbiddenError{msg: "The admin api is disabled"})
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader(adminKeyHeader)), []byte(handler.AdminKey)) != 1 {
+		abortWithError(c, unauthorizedError{msg: "The admin key is required in the " + adminKeyHeader + " header"})
+	}
+}
+
+// IssueAPIClient answers the new client with its key, which is not answered again
+func (handler RitoHandler) IssueAPIClient(c *gin.Context) (interface{}, error) {
+	var request APIClientRequest
+	_ = c.ShouldBindJSON(&request)
+	var invalidRequestsPerMinute *FieldError
+	if request.RequestsPerMinute < 0 {
+		invalidRequestsPerMinute = &FieldError{Field: "requests_per_minute", Reason: "should not be negative"}
+	}
+	if err := validate(checkRequired("name", request.Name), invalidRequestsPerMinute); err != nil {
+		return nil, err
+	}
+	return handler.APIClientService.Issue(request.Name, request.RequestsPerMinute)
+}
+
+func (handler RitoHandler) FindAPIClients(c *gin.Context) (interface{}, error) {
+	return handler.APIClientService.FindClients()
+}
+
+func (handler RitoHandler) RevokeAPIClient(c *gin.Context) (interface{}, error) {
+	if err := handler.APIClientService.Revoke(c.Param("client_id")); err != nil {
+		return nil, err
+	}
+	handler.RateLimiter.forget(c.Param("client_id"))
+	return nil, nil
+}
+
+func ceilSeconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
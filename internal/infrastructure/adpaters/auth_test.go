@@ -0,0 +1,141 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("Test a client can burst its quota and then gets a request every refill", func(t *testing.T) {
+		now := time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC)
+		limiter := NewRateLimiter()
+		limiter.now = func() time.Time { return now }
+
+		for remaining := 59; remaining >= 0; remaining-- {
+			limit := limiter.take("test_id", 60)
+			assert.True(t, limit.allowed)
+			assert.Equal(t, remaining, limit.remaining)
+		}
+		limit := limiter.take("test_id", 60)
+		assert.False(t, limit.allowed)
+		assert.Equal(t, time.Second, limit.retryAfter)
+		assert.Equal(t, time.Minute, limit.reset)
+		assert.True(t, limiter.take("other_id", 60).allowed, "buckets are per client")
+
+		now = now.Add(time.Second)
+		assert.True(t, limiter.take("test_id", 60).allowed)
+		assert.False(t, limiter.take("test_id", 60).allowed)
+	})
+}
+
+func TestAPIKeysEndToEnd(t *testing.T) {
+	t.Run("Test keys are issued, limited and revoked", func(t *testing.T) {
+		router := newAuthRouter(t)
+
+		recorder := serve(router, http.MethodGet, "/api/v1/ping", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+		recorder = serve(router, http.MethodPost, "/api/admin/api-clients", `{"name": "bot"}`, nil)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assertErrorCode(t, recorder, "unauthorized")
+
+		adminHeaders := map[string]string{adminKeyHeader: "admin_key"}
+		recorder = serve(router, http.MethodPost, "/api/admin/api-clients", `{"name": "bot", "requests_per_minute": 2}`, adminHeaders)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var issued struct {
+			Data domain.APIClient `json:"data"`
+		}
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &issued))
+		assert.NotEmpty(t, issued.Data.Key)
+		assert.Equal(t, 2, issued.Data.RequestsPerMinute)
+
+		clientHeaders := map[string]string{apiKeyHeader: issued.Data.Key}
+		for _, remaining := range []string{"1", "0"} {
+			recorder = serve(router, http.MethodGet, "/api/v1/ping", "", clientHeaders)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, "2", recorder.Header().Get("X-RateLimit-Limit"))
+			assert.Equal(t, remaining, recorder.Header().Get("X-RateLimit-Remaining"))
+		}
+		recorder = serve(router, http.MethodGet, "/api/v2/health", "", clientHeaders)
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+		assertErrorCode(t, recorder, "rate_limited")
+
+		recorder = serve(router, http.MethodGet, "/api/admin/api-clients", "", adminHeaders)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.NotContains(t, recorder.Body.String(), issued.Data.Key)
+
+		recorder = serve(router, http.MethodDelete, "/api/admin/api-clients/"+issued.Data.Id, "", adminHeaders)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+		recorder = serve(router, http.MethodGet, "/api/v1/ping", "", clientHeaders)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		recorder = serve(router, http.MethodDelete, "/api/admin/api-clients/"+issued.Data.Id, "", adminHeaders)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Test a client written by hand without requests per minute gets the default ones", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "api_clients")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "api_clients.json")
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"clients": [{"id": "bot", "name": "bot", "key": "bot_key"}]}`), 0600))
+		store, err := storage.NewFileAPIClientStore(path)
+		assert.Nil(t, err)
+		router := NewRouter(RitoHandler{APIClientService: application.NewAPIClientService(store, 60)})
+
+		recorder := serve(router, http.MethodGet, "/api/v1/ping", "", map[string]string{apiKeyHeader: "bot_key"})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "60", recorder.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, "59", recorder.Header().Get("X-RateLimit-Remaining"))
+	})
+
+	t.Run("Test the admin api is disabled without admin key", func(t *testing.T) {
+		router := NewRouter(RitoHandler{})
+
+		recorder := serve(router, http.MethodGet, "/api/admin/api-clients", "", map[string]string{adminKeyHeader: ""})
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		recorder = serve(router, http.MethodGet, "/api/v1/ping", "", nil)
+		assert.Equal(t, http.StatusOK, recorder.Code, "the api is open without api clients")
+	})
+}
+
+func newAuthRouter(t *testing.T) *gin.Engine {
+	dir, err := ioutil.TempDir("", "api_clients")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	store, err := storage.NewFileAPIClientStore(filepath.Join(dir, "api_clients.json"))
+	assert.Nil(t, err)
+
+	return NewRouter(RitoHandler{
+		APIClientService: application.NewAPIClientService(store, 60),
+		AdminKey:         "admin_key",
+	})
+}
+
+func serve(router *gin.Engine, method string, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func assertErrorCode(t *testing.T, recorder *httptest.ResponseRecorder, code string) {
+	var envelope ErrorEnvelope
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+	assert.Equal(t, code, envelope.Error.Code)
+}
@@ -1,14 +1,53 @@
 package infrastructure
 
 import (
+	"errors"
 	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/metrics"
 	"github.com/gin-gonic/gin"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 //create the handler with the needed dependencies.
 type RitoHandler struct {
-	MatchService application.MatchService
+	MatchService       application.MatchService
+	HealthService      application.HealthService
+	LiveGameService    application.LiveGameService
+	WatchlistService   application.WatchlistService
+	RankHistoryService application.RankHistoryService
+	GroupService       application.GroupService
+	LadderService      application.LadderService
+	StatusService      application.StatusService
+	ClashService       application.ClashService
+	SummonerService    application.SummonerService
+	// Regions are the ones with a configured host, requests for other regions are rejected
+	Regions []string
+	// APIClientService authenticates the api keys, the api is open without it
+	APIClientService application.APIClientService
+	// RateLimiter limits the requests of each api client, NewRouter creates one when nil
+	RateLimiter *RateLimiter
+	// AdminKey authenticates the admin api, which is disabled without it
+	AdminKey string
+	// Logger is the one of every request, carrying its request id, the default logger when nil
+	Logger *logging.Logger
+	// Metrics are answered at /metrics, NewRouter creates a registry when nil
+	Metrics *metrics.Registry
+}
+
+type GroupRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// SummonerRequest identifies a summoner in the request bodies
+type SummonerRequest struct {
+	Region       string `json:"region" binding:"required"`
+	SummonerName string `json:"summoner_name" binding:"required"`
 }
 
 func (handler RitoHandler) Ping(c *gin.Context) {
@@ -17,30 +56,547 @@ func (handler RitoHandler) Ping(c *gin.Context) {
 	})
 }
 
+func (handler RitoHandler) Health(c *gin.Context) {
+	c.JSON(http.StatusOK, handler.HealthService.CheckHealth())
+}
+
+// Liveness answers while the process is able to serve requests, no matter the dependencies
+func (handler RitoHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{
+		Msg: "ok",
+	})
+}
+
+// Readiness answers 503 while a dependency failed, so the instance is taken out of the load balancer
+func (handler RitoHandler) Readiness(c *gin.Context) {
+	readiness := handler.HealthService.CheckReadiness(c.Request.Context())
+	status := http.StatusOK
+	if readiness.Status != domain.ReadinessReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, readiness)
+}
+
 func (handler RitoHandler) FindMatchInfoByRegionAndSummoner(c *gin.Context) {
-	region, exists := c.GetQuery("region")
-	if !exists {
+	region, summonerName := c.Query("region"), c.Query("summoner_name")
+	err := validate(handler.checkRegion("region", region), checkSummonerName("summoner_name", summonerName))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	match, err := handler.MatchService.FindCurrentMatchByRegionAndSummonerName(c.Request.Context(), region, summonerName)
+	if err != nil {
+		response := Response{Msg: err.Error()}
+		if isUpstreamFailure(err) {
+			// the ongoing incidents tell whether it is rito that is failing
+			response.Notices = handler.StatusService.FindActiveNotices(c.Request.Context(), region)
+		}
+		c.JSON(errorStatus(err), response)
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// FindFeaturedMatchesByRegion answers the featured games of the region with the leagues of every participant
+func (handler RitoHandler) FindFeaturedMatchesByRegion(c *gin.Context) {
+	region := c.Query("region")
+	if err := validate(handler.checkRegion("region", region)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	featured, err := handler.MatchService.FindFeaturedMatchesByRegion(c.Request.Context(), region)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, featured)
+}
+
+// StreamMatchByRegionAndSummoner pushes the live game events of the summoner as server sent events
+func (handler RitoHandler) StreamMatchByRegionAndSummoner(c *gin.Context) {
+	region, summonerName := c.Query("region"), c.Query("summoner_name")
+	err := validate(handler.checkRegion("region", region), checkSummonerName("summoner_name", summonerName))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	events, unsubscribe, err := handler.LiveGameService.Subscribe(c.Request.Context(), region, summonerName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+	defer unsubscribe()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, open := <-events:
+			if !open {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		}
+	})
+}
+
+func (handler RitoHandler) Watch(c *gin.Context) {
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	watched, err := handler.WatchlistService.Watch(c.Request.Context(), request.Region, request.SummonerName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, watched)
+}
+
+func (handler RitoHandler) Unwatch(c *gin.Context) {
+	if err := handler.WatchlistService.Unwatch(c.Param("region"), c.Param("summoner_id")); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (handler RitoHandler) FindWatchedSummoners(c *gin.Context) {
+	watched, err := handler.WatchlistService.FindWatchedSummoners()
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, watched)
+}
+
+func (handler RitoHandler) FindWebhookDeliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
 		c.JSON(http.StatusBadRequest, Response{
-			Msg: "The parameter region is required",
+			Msg: "The parameter limit should be a positive number",
 		})
 		return
 	}
-	summonerName, exists := c.GetQuery("summoner_name")
-	if !exists {
+
+	deliveries, err := handler.WatchlistService.FindDeliveries(limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (handler RitoHandler) TrackRankHistory(c *gin.Context) {
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	tracked, err := handler.RankHistoryService.Track(c.Request.Context(), request.Region, request.SummonerName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, tracked)
+}
+
+func (handler RitoHandler) FindRankHistory(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter days should be a positive number",
+		})
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	history, err := handler.RankHistoryService.FindRankHistory(
+		c.Param("region"),
+		c.Param("summoner_id"),
+		c.DefaultQuery("queue", domain.SoloQueueType),
+		since,
+	)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (handler RitoHandler) FindArchivedGames(c *gin.Context) {
+	region, summonerId := c.Query("region"), c.Query("summoner_id")
+	limit, limitErr := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	var limitCheck *FieldError
+	if limitErr != nil || limit <= 0 {
+		limitCheck = &FieldError{Field: "limit", Reason: "should be a positive number"}
+	}
+	err := validate(handler.checkRegion("region", region), checkRequired("summoner_id", summonerId), limitCheck)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	games, err := handler.MatchService.FindArchivedGames(region, summonerId, limit)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, games)
+}
+
+func (handler RitoHandler) FindArchivedGame(c *gin.Context) {
+	gameId, err := strconv.ParseInt(c.Param("game_id"), 10, 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, Response{
-			Msg: "The parameter summoner_name is required",
+			Msg: "The parameter game_id should be a number",
 		})
 		return
 	}
 
-	match, err := handler.MatchService.FindCurrentMatchByRegionAndSummonerName(region, summonerName)
+	game, err := handler.MatchService.FindArchivedGame(c.Param("region"), gameId)
 	if err != nil {
-		//TODO improve the error handling
-		c.JSON(http.StatusInternalServerError, Response{
-			Msg: err.Error(),
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+func (handler RitoHandler) CreateGroup(c *gin.Context) {
+	var request GroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The field name is required",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, match)
+	group, err := handler.GroupService.CreateGroup(request.Name)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+func (handler RitoHandler) FindGroup(c *gin.Context) {
+	groupId, ok := groupIdParam(c)
+	if !ok {
+		return
+	}
+
+	group, err := handler.GroupService.FindGroup(groupId)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+func (handler RitoHandler) AddGroupMember(c *gin.Context) {
+	groupId, ok := groupIdParam(c)
+	if !ok {
+		return
+	}
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	group, err := handler.GroupService.AddMember(c.Request.Context(), groupId, request.Region, request.SummonerName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+func (handler RitoHandler) RemoveGroupMember(c *gin.Context) {
+	groupId, ok := groupIdParam(c)
+	if !ok {
+		return
+	}
+
+	group, err := handler.GroupService.RemoveMember(groupId, c.Param("region"), c.Param("summoner_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+func (handler RitoHandler) FindGroupLeaderboard(c *gin.Context) {
+	groupId, ok := groupIdParam(c)
+	if !ok {
+		return
+	}
+
+	leaderboard, err := handler.GroupService.FindLeaderboard(c.Request.Context(), groupId)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
+
+func (handler RitoHandler) FindApexLadder(c *gin.Context) {
+	queue, ok := queueParam(c)
+	if !ok {
+		return
+	}
+	tier := strings.ToUpper(c.Param("tier"))
+	if !domain.IsApexTier(tier) {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter tier should be CHALLENGER, GRANDMASTER or MASTER",
+		})
+		return
+	}
+	page, ok := pageParam(c)
+	if !ok {
+		return
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if err != nil || pageSize <= 0 || pageSize > 200 {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter page_size should be a number between 1 and 200",
+		})
+		return
+	}
+
+	ladder, err := handler.LadderService.FindApexLadder(c.Request.Context(), c.Param("region"), queue, tier, page, pageSize)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+func (handler RitoHandler) FindLeagueEntries(c *gin.Context) {
+	queue, ok := queueParam(c)
+	if !ok {
+		return
+	}
+	tier := strings.ToUpper(c.Param("tier"))
+	division := strings.ToUpper(c.Param("division"))
+	if !domain.IsValidTier(tier, division) || domain.IsApexTier(tier) {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameters tier and division should be a tier below master and a division between I and IV",
+		})
+		return
+	}
+	page, ok := pageParam(c)
+	if !ok {
+		return
+	}
+
+	ladder, err := handler.LadderService.FindLeagueEntries(c.Request.Context(), c.Param("region"), queue, tier, division, page)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ladder)
+}
+
+func (handler RitoHandler) FindLadderStanding(c *gin.Context) {
+	queue, ok := queueParam(c)
+	if !ok {
+		return
+	}
+
+	standing, err := handler.LadderService.FindStanding(c.Request.Context(), c.Param("region"), queue, c.Param("summoner_id"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, standing)
+}
+
+func (handler RitoHandler) FindPlatformStatus(c *gin.Context) {
+	status, err := handler.StatusService.FindPlatformStatus(c.Request.Context(), c.Param("region"))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (handler RitoHandler) ScoutClashTeam(c *gin.Context) {
+	region, summonerName := c.Query("region"), c.Query("summoner_name")
+	err := validate(handler.checkRegion("region", region), checkSummonerName("summoner_name", summonerName))
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	team, err := handler.ClashService.ScoutTeam(c.Request.Context(), region, summonerName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+func (handler RitoHandler) FindClashTournaments(c *gin.Context) {
+	region := c.Query("region")
+	if err := validate(handler.checkRegion("region", region)); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	tournaments, err := handler.ClashService.FindTournaments(c.Request.Context(), region)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tournaments)
+}
+
+// queueParam answers a bad request when the queue is not a ranked summoner's rift queue
+func queueParam(c *gin.Context) (string, bool) {
+	queue := c.Param("queue")
+	if queue != domain.SoloQueueType && queue != domain.FlexQueueType {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter queue should be RANKED_SOLO_5x5 or RANKED_FLEX_SR",
+		})
+		return "", false
+	}
+	return queue, true
+}
+
+// pageParam answers a bad request when the page is not a positive number
+func pageParam(c *gin.Context) (int, bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page <= 0 {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter page should be a positive number",
+		})
+		return 0, false
+	}
+	return page, true
+}
+
+// groupIdParam answers a bad request when the group id is not a number
+func groupIdParam(c *gin.Context) (int64, bool) {
+	groupId, err := strconv.ParseInt(c.Param("group_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{
+			Msg: "The parameter group_id should be a number",
+		})
+		return 0, false
+	}
+	return groupId, true
+}
+
+// bindSummonerRequest validates the region and summoner name of the body
+func (handler RitoHandler) bindSummonerRequest(c *gin.Context) (SummonerRequest, error) {
+	var request SummonerRequest
+	// the presence of the fields is validated below with the rest of the rules
+	_ = c.ShouldBindJSON(&request)
+	err := validate(
+		handler.checkRegion("region", request.Region),
+		checkSummonerName("summoner_name", request.SummonerName),
+	)
+	return request, err
+}
+
+// validateRegionParam rejects the requests whose region path param has no configured host
+func (handler RitoHandler) validateRegionParam(c *gin.Context) {
+	region, exists := c.Params.Get("region")
+	if !exists {
+		return
+	}
+	if err := validate(handler.checkRegion("region", region)); err != nil {
+		abortWithError(c, err)
+	}
+}
+
+// abortWithError answers the error of a middleware in the format of the api of the route
+func abortWithError(c *gin.Context, err error) {
+	if strings.HasPrefix(c.FullPath(), "/api/v1") {
+		handleError(c, err)
+	} else {
+		handleErrorV2(c, err, nil)
+	}
+	c.Abort()
+}
+
+func handleError(c *gin.Context, err error) {
+	response := Response{Msg: err.Error()}
+	var invalidErr validationError
+	if errors.As(err, &invalidErr) {
+		response.Fields = invalidErr.fields
+	}
+	c.JSON(errorStatus(err), response)
+}
+
+// isUpstreamFailure tells whether the error comes from rito being down, when its incidents are worth looking up
+func isUpstreamFailure(err error) bool {
+	var unavailableErr application.UpstreamUnavailableError
+	var upstreamErr application.UpstreamError
+	return errors.As(err, &unavailableErr) ||
+		(errors.As(err, &upstreamErr) && upstreamErr.Status >= http.StatusInternalServerError)
+}
+
+func errorStatus(err error) int {
+	if errors.Is(err, application.ErrSummonerNotFound) ||
+		errors.Is(err, application.ErrMatchNotFound) ||
+		errors.Is(err, application.ErrArchivedGameNotFound) ||
+		errors.Is(err, application.ErrGroupNotFound) ||
+		errors.Is(err, application.ErrClashTeamNotFound) ||
+		errors.Is(err, application.ErrAPIClientNotFound) {
+		return http.StatusNotFound
+	}
+	var unauthorizedErr unauthorizedError
+	if errors.As(err, &unauthorizedErr) {
+		return http.StatusUnauthorized
+	}
+	var forbiddenErr forbiddenError
+	if errors.As(err, &forbiddenErr) {
+		return http.StatusForbidden
+	}
+	var rateLimitedErr rateLimitedError
+	if errors.As(err, &rateLimitedErr) {
+		return http.StatusTooManyRequests
+	}
+	var unknownRegionErr application.UnknownRegionError
+	var invalidErr invalidRequestError
+	var validationErr validationError
+	if errors.As(err, &unknownRegionErr) || errors.As(err, &invalidErr) || errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	var unavailableErr application.UpstreamUnavailableError
+	if errors.As(err, &unavailableErr) {
+		return http.StatusServiceUnavailable
+	}
+	//TODO improve the error handling
+	return http.StatusInternalServerError
 }
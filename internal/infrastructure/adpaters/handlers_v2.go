@@ -0,0 +1,282 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusServiceUnavailable:  "upstream_unavailable",
+	http.StatusInternalServerError: "internal_error",
+}
+
+// invalidRequestError is answered as a bad request
+type invalidRequestError struct {
+	msg string
+}
+
+func (e invalidRequestError) Error() string {
+	return e.msg
+}
+
+// handlerV2 returns the data of the response, nil data answers without body
+type handlerV2 func(c *gin.Context) (interface{}, error)
+
+// v2 answers the data of the handler in an envelope with the given status, or the error in an error envelope.
+// Upstream failures of a region include its ongoing rito incidents.
+func (handler RitoHandler) v2(status int, handle handlerV2) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := handle(c)
+		if err != nil {
+			var notices []domain.PlatformNotice
+			if region := c.Param("region"); isUpstreamFailure(err) && region != "" {
+				notices = handler.StatusService.FindActiveNotices(c.Request.Context(), region)
+			}
+			handleErrorV2(c, err, notices)
+			return
+		}
+		if data == nil {
+			c.Status(status)
+			return
+		}
+		c.JSON(status, DataEnvelope{Data: data})
+	}
+}
+
+func handleErrorV2(c *gin.Context, err error, notices []domain.PlatformNotice) {
+	status := errorStatus(err)
+	body := ErrorBody{Code: errorCodes[status], Message: err.Error(), Notices: notices}
+	var invalidErr validationError
+	if errors.As(err, &invalidErr) {
+		body.Fields = invalidErr.fields
+	}
+	c.JSON(status, ErrorEnvelope{Error: body})
+}
+
+func (handler RitoHandler) FindSummonerV2(c *gin.Context) (interface{}, error) {
+	if err := validate(checkSummonerName("summoner", c.Param("summoner"))); err != nil {
+		return nil, err
+	}
+	return handler.SummonerService.FindSummonerByRegionAndName(c.Request.Context(), c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindLiveGameV2(c *gin.Context) (interface{}, error) {
+	if err := validate(checkSummonerName("summoner", c.Param("summoner"))); err != nil {
+		return nil, err
+	}
+	return handler.MatchService.FindCurrentMatchByRegionAndSummonerName(c.Request.Context(), c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindLeaguesV2(c *gin.Context) (interface{}, error) {
+	return handler.SummonerService.FindLeaguesByRegionAndSummonerId(c.Request.Context(), c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindRankHistoryV2(c *gin.Context) (interface{}, error) {
+	days, err := positiveQuery(c, "days", 30)
+	if err != nil {
+		return nil, err
+	}
+	return handler.RankHistoryService.FindRankHistory(
+		c.Param("region"),
+		c.Param("summoner"),
+		c.DefaultQuery("queue", domain.SoloQueueType),
+		time.Now().AddDate(0, 0, -days),
+	)
+}
+
+func (handler RitoHandler) FindArchivedGamesV2(c *gin.Context) (interface{}, error) {
+	limit, err := positiveQuery(c, "limit", 20)
+	if err != nil {
+		return nil, err
+	}
+	return handler.MatchService.FindArchivedGames(c.Param("region"), c.Param("summoner"), limit)
+}
+
+func (handler RitoHandler) FindArchivedGameV2(c *gin.Context) (interface{}, error) {
+	gameId, err := strconv.ParseInt(c.Param("game_id"), 10, 64)
+	if err != nil {
+		return nil, invalidRequestError{msg: "The parameter game_id should be a number"}
+	}
+	return handler.MatchService.FindArchivedGame(c.Param("region"), gameId)
+}
+
+func (handler RitoHandler) ScoutClashTeamV2(c *gin.Context) (interface{}, error) {
+	if err := validate(checkSummonerName("summoner", c.Param("summoner"))); err != nil {
+		return nil, err
+	}
+	return handler.ClashService.ScoutTeam(c.Request.Context(), c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindClashTournamentsV2(c *gin.Context) (interface{}, error) {
+	return handler.ClashService.FindTournaments(c.Request.Context(), c.Param("region"))
+}
+
+func (handler RitoHandler) FindFeaturedMatchesV2(c *gin.Context) (interface{}, error) {
+	return handler.MatchService.FindFeaturedMatchesByRegion(c.Request.Context(), c.Param("region"))
+}
+
+func (handler RitoHandler) FindPlatformStatusV2(c *gin.Context) (interface{}, error) {
+	return handler.StatusService.FindPlatformStatus(c.Request.Context(), c.Param("region"))
+}
+
+func (handler RitoHandler) FindApexLadderV2(c *gin.Context) (interface{}, error) {
+	queue, err := queueParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	tier := strings.ToUpper(c.Param("tier"))
+	if !domain.IsApexTier(tier) {
+		return nil, invalidRequestError{msg: "The parameter tier should be CHALLENGER, GRANDMASTER or MASTER"}
+	}
+	page, err := positiveQuery(c, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	pageSize, err := positiveQuery(c, "page_size", 50)
+	if err != nil || pageSize > 200 {
+		return nil, invalidRequestError{msg: "The parameter page_size should be a number between 1 and 200"}
+	}
+	return handler.LadderService.FindApexLadder(c.Request.Context(), c.Param("region"), queue, tier, page, pageSize)
+}
+
+func (handler RitoHandler) FindLeagueEntriesV2(c *gin.Context) (interface{}, error) {
+	queue, err := queueParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	tier := strings.ToUpper(c.Param("tier"))
+	division := strings.ToUpper(c.Param("division"))
+	if !domain.IsValidTier(tier, division) || domain.IsApexTier(tier) {
+		return nil, invalidRequestError{
+			msg: "The parameters tier and division should be a tier below master and a division between I and IV",
+		}
+	}
+	page, err := positiveQuery(c, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	return handler.LadderService.FindLeagueEntries(c.Request.Context(), c.Param("region"), queue, tier, division, page)
+}
+
+func (handler RitoHandler) FindLadderStandingV2(c *gin.Context) (interface{}, error) {
+	queue, err := queueParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.LadderService.FindStanding(c.Request.Context(), c.Param("region"), queue, c.Param("summoner"))
+}
+
+func (handler RitoHandler) TrackRankHistoryV2(c *gin.Context) (interface{}, error) {
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.RankHistoryService.Track(c.Request.Context(), request.Region, request.SummonerName)
+}
+
+func (handler RitoHandler) FindWatchedSummonersV2(c *gin.Context) (interface{}, error) {
+	return handler.WatchlistService.FindWatchedSummoners()
+}
+
+func (handler RitoHandler) WatchV2(c *gin.Context) (interface{}, error) {
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.WatchlistService.Watch(c.Request.Context(), request.Region, request.SummonerName)
+}
+
+func (handler RitoHandler) UnwatchV2(c *gin.Context) (interface{}, error) {
+	return nil, handler.WatchlistService.Unwatch(c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindWebhookDeliveriesV2(c *gin.Context) (interface{}, error) {
+	limit, err := positiveQuery(c, "limit", 50)
+	if err != nil {
+		return nil, err
+	}
+	return handler.WatchlistService.FindDeliveries(limit)
+}
+
+func (handler RitoHandler) CreateGroupV2(c *gin.Context) (interface{}, error) {
+	var request GroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		return nil, invalidRequestError{msg: "The field name is required"}
+	}
+	return handler.GroupService.CreateGroup(request.Name)
+}
+
+func (handler RitoHandler) FindGroupV2(c *gin.Context) (interface{}, error) {
+	groupId, err := groupIdParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.GroupService.FindGroup(groupId)
+}
+
+func (handler RitoHandler) AddGroupMemberV2(c *gin.Context) (interface{}, error) {
+	groupId, err := groupIdParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	request, err := handler.bindSummonerRequest(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.GroupService.AddMember(c.Request.Context(), groupId, request.Region, request.SummonerName)
+}
+
+func (handler RitoHandler) RemoveGroupMemberV2(c *gin.Context) (interface{}, error) {
+	groupId, err := groupIdParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.GroupService.RemoveMember(groupId, c.Param("region"), c.Param("summoner"))
+}
+
+func (handler RitoHandler) FindGroupLeaderboardV2(c *gin.Context) (interface{}, error) {
+	groupId, err := groupIdParamV2(c)
+	if err != nil {
+		return nil, err
+	}
+	return handler.GroupService.FindLeaderboard(c.Request.Context(), groupId)
+}
+
+func (handler RitoHandler) HealthV2(c *gin.Context) (interface{}, error) {
+	return handler.HealthService.CheckHealth(), nil
+}
+
+func positiveQuery(c *gin.Context, name string, defaultValue int) (int, error) {
+	value, err := strconv.Atoi(c.DefaultQuery(name, strconv.Itoa(defaultValue)))
+	if err != nil || value <= 0 {
+		return 0, invalidRequestError{msg: fmt.Sprintf("The parameter %s should be a positive number", name)}
+	}
+	return value, nil
+}
+
+func queueParamV2(c *gin.Context) (string, error) {
+	queue := c.Param("queue")
+	if queue != domain.SoloQueueType && queue != domain.FlexQueueType {
+		return "", invalidRequestError{msg: "The parameter queue should be RANKED_SOLO_5x5 or RANKED_FLEX_SR"}
+	}
+	return queue, nil
+}
+
+func groupIdParamV2(c *gin.Context) (int64, error) {
+	groupId, err := strconv.ParseInt(c.Param("group_id"), 10, 64)
+	if err != nil {
+		return 0, invalidRequestError{msg: "The parameter group_id should be a number"}
+	}
+	return groupId, nil
+}
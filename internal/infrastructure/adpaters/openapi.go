@@ -0,0 +1,699 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// docsPage renders the openapi document by itself, the page loads no third party script
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>loleros-api</title>
+	<meta charset="utf-8"/>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<style>
+		body { font-family: sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+		section { border: 1px solid #ddd; border-radius: 4px; margin: 1em 0; padding: 0 1em 1em; }
+		h3 code { background: #eef; margin-right: .5em; padding: .1em .4em; text-transform: uppercase; }
+		td { padding: .2em 1em .2em 0; vertical-align: top; }
+	</style>
+</head>
+<body>
+	<h1>loleros-api</h1>
+	<div id="docs">Loading /api/openapi.json</div>
+	<script>
+	function el(tag, text, children) {
+		var node = document.createElement(tag);
+		if (text) node.textContent = text;
+		(children || []).forEach(function (child) { node.appendChild(child); });
+		return node;
+	}
+	function row(cells) {
+		return el("tr", "", cells.map(function (cell) { return el("td", cell); }));
+	}
+	// describe renders a schema as a short type, like Match or []League
+	function describe(schema) {
+		if (!schema) return "";
+		if (schema.$ref) return schema.$ref.split("/").pop();
+		if (schema.type === "array") return "[]" + describe(schema.items);
+		if (schema.additionalProperties) return "map[string]" + describe(schema.additionalProperties);
+		if (schema.properties) {
+			return "{" + Object.keys(schema.properties).map(function (name) {
+				return name + ": " + describe(schema.properties[name]);
+			}).join(", ") + "}";
+		}
+		return schema.format ? schema.type + " (" + schema.format + ")" : schema.type;
+	}
+	function describeContent(content) {
+		return Object.keys(content || {}).map(function (type) {
+			return type + " " + describe(content[type].schema);
+		}).join(", ");
+	}
+	function render(doc) {
+		var root = document.getElementById("docs");
+		root.textContent = "";
+		Object.keys(doc.paths).sort().forEach(function (path) {
+			Object.keys(doc.paths[path]).forEach(function (method) {
+				var operation = doc.paths[path][method];
+				var rows = (operation.parameters || []).map(function (param) {
+					return row([param.name + (param.required ? " *" : ""), param.in, describe(param.schema), param.description]);
+				});
+				if (operation.requestBody) rows.push(row(["body", "body", describeContent(operation.requestBody.content), ""]));
+				Object.keys(operation.responses).sort().forEach(function (status) {
+					var response = operation.responses[status];
+					rows.push(row([status, "response", describeContent(response.content), response.description]));
+				});
+				root.appendChild(el("section", "", [
+					el("h3", "", [el("code", method), document.createTextNode(path)]),
+					el("p", operation.summary),
+					el("table", "", rows)
+				]));
+			});
+		});
+		root.appendChild(el("h2", "Schemas"));
+		Object.keys(doc.components.schemas).sort().forEach(function (name) {
+			var schema = doc.components.schemas[name];
+			var rows = Object.keys(schema.properties || {}).map(function (field) {
+				var required = (schema.required || []).indexOf(field) >= 0;
+				return row([field + (required ? " *" : ""), describe(schema.properties[field])]);
+			});
+			root.appendChild(el("section", "", [el("h3", name), el("table", "", rows)]));
+		});
+	}
+	fetch("/api/openapi.json")
+		.then(function (response) { return response.json(); })
+		.then(render)
+		.catch(function (err) {
+			document.getElementById("docs").textContent = "Could not load /api/openapi.json: " + err;
+		});
+	</script>
+</body>
+</html>
+`
+
+// integerParams are the path params that are numbers, every other one is a string
+var integerParams = map[string]bool{"group_id": true, "game_id": true}
+
+var paramDescriptions = map[string]string{
+	"region":      "Rito platform, like euw1 or la2",
+	"summoner":    "Summoner name for live-game, clash-team and the summoner itself, summoner id for the other resources",
+	"summoner_id": "Encrypted summoner id",
+	"group_id":    "Id of the group",
+	"game_id":     "Id of the game",
+	"client_id":   "Id of the api client",
+	"queue":       "RANKED_SOLO_5x5 or RANKED_FLEX_SR",
+	"tier":        "Tier of the ladder, like CHALLENGER or GOLD",
+	"division":    "Division of the tier, from I to IV",
+}
+
+// routeDoc describes a route of the router, the path params are taken from the route itself
+type routeDoc struct {
+	summary string
+	query   []queryDoc
+	// body is a value of the request body model, nil when the route has no body
+	body interface{}
+	// status of the successful response, 200 when zero
+	status int
+	// response is a value of the response model, nil when the route answers without content
+	response interface{}
+	// stream answers the response model as server sent events
+	stream bool
+	// contentType of the string responses, text/html when empty
+	contentType string
+	notFound    bool
+}
+
+type queryDoc struct {
+	name        string
+	description string
+	required    bool
+	integer     bool
+}
+
+var (
+	regionQuery       = queryDoc{name: "region", description: paramDescriptions["region"], required: true}
+	summonerNameQuery = queryDoc{name: "summoner_name", description: "Summoner name", required: true}
+	pageQuery         = queryDoc{name: "page", description: "Page starting at 1, 1 by default", integer: true}
+	pageSizeQuery     = queryDoc{name: "page_size", description: "Entries per page up to 200, 50 by default", integer: true}
+	daysQuery         = queryDoc{name: "days", description: "Days of history, 30 by default", integer: true}
+	queueQuery        = queryDoc{name: "queue", description: "Queue of the history, RANKED_SOLO_5x5 by default"}
+)
+
+func limitQuery(defaultValue int) queryDoc {
+	return queryDoc{name: "limit", description: fmt.Sprintf("Max items, %d by default", defaultValue), integer: true}
+}
+
+// routeDocs documents every route of NewRouter by method and gin path
+var routeDocs = map[string]routeDoc{
+	"GET /api/openapi.json": {summary: "This openapi document", response: map[string]interface{}{}},
+	"GET /api/docs":         {summary: "Page rendering this openapi document", response: ""},
+	"GET /metrics":          {summary: "Prometheus metrics", response: "", contentType: "text/plain"},
+	"GET /healthz":          {summary: "Liveness probe, answers while the process is up", response: Response{}},
+	"GET /readyz":           {summary: "Readiness probe, answers 503 while the rito token, the cache or the store failed", response: domain.Readiness{}},
+
+	"GET /api/v1/ping":   {summary: "Checks the api is up", response: Response{}},
+	"GET /api/v1/health": {summary: "Circuit breaker state of each region", response: domain.Health{}},
+	"GET /api/v1/rito/match": {
+		summary:  "Current game of a summoner with the leagues of every participant",
+		query:    []queryDoc{regionQuery, summonerNameQuery},
+		response: domain.Match{},
+		notFound: true,
+	},
+	"GET /api/v1/rito/match/stream": {
+		summary:  "Live game events of a summoner",
+		query:    []queryDoc{regionQuery, summonerNameQuery},
+		response: domain.LiveGameEvent{},
+		stream:   true,
+		notFound: true,
+	},
+	"GET /api/v1/rito/featured": {
+		summary:  "Featured games of a region with the leagues of every participant",
+		query:    []queryDoc{regionQuery},
+		response: domain.FeaturedMatches{},
+	},
+	"GET /api/v1/watchlist": {summary: "Watched summoners", response: []domain.WatchedSummoner{}},
+	"POST /api/v1/watchlist": {
+		summary:  "Watches the games of a summoner",
+		body:     SummonerRequest{},
+		status:   http.StatusCreated,
+		response: domain.WatchedSummoner{},
+		notFound: true,
+	},
+	"DELETE /api/v1/watchlist/:region/:summoner_id": {summary: "Stops watching a summoner", status: http.StatusNoContent},
+	"GET /api/v1/watchlist/deliveries": {
+		summary:  "Latest webhook deliveries",
+		query:    []queryDoc{limitQuery(50)},
+		response: []domain.WebhookDelivery{},
+	},
+	"POST /api/v1/rank-history/tracked": {
+		summary:  "Tracks the rank history of a summoner",
+		body:     SummonerRequest{},
+		status:   http.StatusCreated,
+		response: domain.TrackedSummoner{},
+		notFound: true,
+	},
+	"GET /api/v1/rank-history/:region/:summoner_id": {
+		summary:  "Rank history of a tracked summoner",
+		query:    []queryDoc{daysQuery, queueQuery},
+		response: []domain.RankHistoryPoint{},
+	},
+	"GET /api/v1/archived-games": {
+		summary: "Archived games of a summoner, latest first",
+		query: []queryDoc{
+			regionQuery,
+			{name: "summoner_id", description: paramDescriptions["summoner_id"], required: true},
+			limitQuery(20),
+		},
+		response: []domain.ArchivedGame{},
+	},
+	"GET /api/v1/archived-games/:region/:game_id": {
+		summary:  "Archived game",
+		response: domain.ArchivedGame{},
+		notFound: true,
+	},
+	"POST /api/v1/groups": {
+		summary:  "Creates a group",
+		body:     GroupRequest{},
+		status:   http.StatusCreated,
+		response: domain.Group{},
+	},
+	"GET /api/v1/groups/:group_id": {summary: "Group with its members", response: domain.Group{}, notFound: true},
+	"POST /api/v1/groups/:group_id/members": {
+		summary:  "Adds a summoner to a group",
+		body:     SummonerRequest{},
+		response: domain.Group{},
+		notFound: true,
+	},
+	"DELETE /api/v1/groups/:group_id/members/:region/:summoner_id": {
+		summary:  "Removes a summoner from a group",
+		response: domain.Group{},
+		notFound: true,
+	},
+	"GET /api/v1/groups/:group_id/leaderboard": {
+		summary:  "Solo queue leaderboard of a group",
+		response: domain.Leaderboard{},
+		notFound: true,
+	},
+	"GET /api/v1/ladders/:region/:queue/apex/:tier": {
+		summary:  "Page of an apex tier ordered by LP",
+		query:    []queryDoc{pageQuery, pageSizeQuery},
+		response: domain.Ladder{},
+	},
+	"GET /api/v1/ladders/:region/:queue/entries/:tier/:division": {
+		summary:  "Page of the entries of a tier and division",
+		query:    []queryDoc{pageQuery},
+		response: domain.Ladder{},
+	},
+	"GET /api/v1/ladders/:region/:queue/standing/:summoner_id": {
+		summary:  "Standing of a summoner relative to the top of the ladder",
+		response: domain.LadderStanding{},
+	},
+	"GET /api/v1/status/:region": {summary: "Rito incidents and maintenances of a region", response: domain.PlatformStatus{}},
+	"GET /api/v1/clash/scout": {
+		summary:  "Clash team of a summoner with the ranks and top champions of every player",
+		query:    []queryDoc{regionQuery, summonerNameQuery},
+		response: domain.ClashTeam{},
+		notFound: true,
+	},
+	"GET /api/v1/clash/tournaments": {
+		summary:  "Clash tournaments of a region",
+		query:    []queryDoc{regionQuery},
+		response: []domain.ClashTournament{},
+	},
+
+	"GET /api/v2/health": {summary: "Circuit breaker state of each region", response: domain.Health{}},
+	"GET /api/v2/regions/:region/status": {
+		summary:  "Rito incidents and maintenances of a region",
+		response: domain.PlatformStatus{},
+	},
+	"GET /api/v2/regions/:region/featured-games": {
+		summary:  "Featured games of a region with the leagues of every participant",
+		response: domain.FeaturedMatches{},
+	},
+	"GET /api/v2/regions/:region/archived-games/:game_id": {
+		summary:  "Archived game",
+		response: domain.ArchivedGame{},
+		notFound: true,
+	},
+	"GET /api/v2/regions/:region/clash/tournaments": {
+		summary:  "Clash tournaments of a region",
+		response: []domain.ClashTournament{},
+	},
+	"GET /api/v2/regions/:region/ladders/:queue/apex/:tier": {
+		summary:  "Page of an apex tier ordered by LP",
+		query:    []queryDoc{pageQuery, pageSizeQuery},
+		response: domain.Ladder{},
+	},
+	"GET /api/v2/regions/:region/ladders/:queue/tiers/:tier/divisions/:division": {
+		summary:  "Page of the entries of a tier and division",
+		query:    []queryDoc{pageQuery},
+		response: domain.Ladder{},
+	},
+	"GET /api/v2/regions/:region/ladders/:queue/standings/:summoner": {
+		summary:  "Standing of a summoner id relative to the top of the ladder",
+		response: domain.LadderStanding{},
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner": {
+		summary:  "Summoner by name with its leagues",
+		response: domain.SummonerProfile{},
+		notFound: true,
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner/live-game": {
+		summary:  "Current game of a summoner name with the leagues of every participant",
+		response: domain.Match{},
+		notFound: true,
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner/clash-team": {
+		summary:  "Clash team of a summoner name with the ranks and top champions of every player",
+		response: domain.ClashTeam{},
+		notFound: true,
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner/leagues": {
+		summary:  "Leagues of a summoner id",
+		response: []domain.League{},
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner/rank-history": {
+		summary:  "Rank history of a tracked summoner id",
+		query:    []queryDoc{daysQuery, queueQuery},
+		response: []domain.RankHistoryPoint{},
+	},
+	"GET /api/v2/regions/:region/summoners/:summoner/archived-games": {
+		summary:  "Archived games of a summoner id, latest first",
+		query:    []queryDoc{limitQuery(20)},
+		response: []domain.ArchivedGame{},
+	},
+	"POST /api/v2/tracked-summoners": {
+		summary:  "Tracks the rank history of a summoner",
+		body:     SummonerRequest{},
+		status:   http.StatusCreated,
+		response: domain.TrackedSummoner{},
+		notFound: true,
+	},
+	"GET /api/v2/watchlist": {summary: "Watched summoners", response: []domain.WatchedSummoner{}},
+	"POST /api/v2/watchlist": {
+		summary:  "Watches the games of a summoner",
+		body:     SummonerRequest{},
+		status:   http.StatusCreated,
+		response: domain.WatchedSummoner{},
+		notFound: true,
+	},
+	"DELETE /api/v2/watchlist/:region/:summoner": {summary: "Stops watching a summoner id", status: http.StatusNoContent},
+	"GET /api/v2/watchlist/deliveries": {
+		summary:  "Latest webhook deliveries",
+		query:    []queryDoc{limitQuery(50)},
+		response: []domain.WebhookDelivery{},
+	},
+	"POST /api/v2/groups": {
+		summary:  "Creates a group",
+		body:     GroupRequest{},
+		status:   http.StatusCreated,
+		response: domain.Group{},
+	},
+	"GET /api/v2/groups/:group_id": {summary: "Group with its members", response: domain.Group{}, notFound: true},
+	"POST /api/v2/groups/:group_id/members": {
+		summary:  "Adds a summoner to a group",
+		body:     SummonerRequest{},
+		response: domain.Group{},
+		notFound: true,
+	},
+	"DELETE /api/v2/groups/:group_id/members/:region/:summoner": {
+		summary:  "Removes a summoner id from a group",
+		response: domain.Group{},
+		notFound: true,
+	},
+	"GET /api/v2/groups/:group_id/leaderboard": {
+		summary:  "Solo queue leaderboard of a group",
+		response: domain.Leaderboard{},
+		notFound: true,
+	},
+
+	"POST /api/admin/api-clients": {
+		summary:  "Issues an api key, answered only once",
+		body:     APIClientRequest{},
+		status:   http.StatusCreated,
+		response: domain.APIClient{},
+	},
+	"GET /api/admin/api-clients": {summary: "Api clients without their keys", response: []domain.APIClient{}},
+	"DELETE /api/admin/api-clients/:client_id": {
+		summary:  "Revokes the api key of a client",
+		status:   http.StatusNoContent,
+		notFound: true,
+	},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	// Security names the scheme authenticating the operation, it only applies when the api keys are enabled
+	Security []map[string][]string `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// newOpenAPIDocument documents the routes with routeDocs, returning the routes without documentation too
+func newOpenAPIDocument(routes gin.RoutesInfo) (*openAPIDocument, []string) {
+	document := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "loleros-api", Version: "2"},
+		Paths:   make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"apiKey":   {Type: "apiKey", In: "header", Name: apiKeyHeader},
+				"adminKey": {Type: "apiKey", In: "header", Name: adminKeyHeader},
+			},
+		},
+	}
+	var undocumented []string
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		doc, exists := routeDocs[key]
+		if !exists {
+			undocumented = append(undocumented, key)
+			continue
+		}
+		path, params := openAPIPath(route.Path)
+		if document.Paths[path] == nil {
+			document.Paths[path] = make(map[string]*openAPIOperation)
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = document.operation(route.Path, params, doc)
+	}
+	sort.Strings(undocumented)
+	return document, undocumented
+}
+
+// openAPIPath turns the gin path params into openapi ones, returning their names
+func openAPIPath(ginPath string) (string, []string) {
+	var params []string
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func (d *openAPIDocument) operation(ginPath string, params []string, doc routeDoc) *openAPIOperation {
+	admin := strings.HasPrefix(ginPath, "/api/admin")
+	// the admin api answers in the v2 envelopes
+	v2 := admin || strings.HasPrefix(ginPath, "/api/v2")
+	versioned := v2 || strings.HasPrefix(ginPath, "/api/v1")
+	tag := "docs"
+	if versioned {
+		tag = strings.Split(ginPath, "/")[2]
+	}
+	operation := &openAPIOperation{
+		Summary:   doc.summary,
+		Tags:      []string{tag},
+		Responses: make(map[string]openAPIResponse),
+	}
+
+	hasRegion := false
+	for _, param := range params {
+		schema := &openAPISchema{Type: "string"}
+		if integerParams[param] {
+			schema = &openAPISchema{Type: "integer", Format: "int64"}
+		}
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        param,
+			In:          "path",
+			Description: paramDescriptions[param],
+			Required:    true,
+			Schema:      schema,
+		})
+		hasRegion = hasRegion || param == "region"
+	}
+	for _, query := range doc.query {
+		schema := &openAPISchema{Type: "string"}
+		if query.integer {
+			schema = &openAPISchema{Type: "integer"}
+		}
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        query.name,
+			In:          "query",
+			Description: query.description,
+			Required:    query.required,
+			Schema:      schema,
+		})
+		hasRegion = hasRegion || query.name == "region"
+	}
+	if doc.body != nil {
+		operation.RequestBody = &openAPIRequestBody{
+			Required: true,
+			Content:  map[string]openAPIMediaType{"application/json": {Schema: d.schema(reflect.TypeOf(doc.body))}},
+		}
+	}
+
+	status := doc.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := openAPIResponse{Description: http.StatusText(status)}
+	switch {
+	case doc.response == nil:
+	case doc.stream:
+		success.Content = map[string]openAPIMediaType{"text/event-stream": {Schema: d.schema(reflect.TypeOf(doc.response))}}
+	case reflect.TypeOf(doc.response).Kind() == reflect.String:
+		contentType := doc.contentType
+		if contentType == "" {
+			contentType = "text/html"
+		}
+		success.Content = map[string]openAPIMediaType{contentType: {Schema: &openAPISchema{Type: "string"}}}
+	case v2:
+		envelope := &openAPISchema{
+			Type:       "object",
+			Properties: map[string]*openAPISchema{"data": d.schema(reflect.TypeOf(doc.response))},
+			Required:   []string{"data"},
+		}
+		success.Content = map[string]openAPIMediaType{"application/json": {Schema: envelope}}
+	default:
+		success.Content = map[string]openAPIMediaType{"application/json": {Schema: d.schema(reflect.TypeOf(doc.response))}}
+	}
+	operation.Responses[strconv.Itoa(status)] = success
+
+	var errorSchema *openAPISchema
+	if v2 {
+		errorSchema = d.schema(reflect.TypeOf(ErrorEnvelope{}))
+	} else {
+		errorSchema = d.schema(reflect.TypeOf(Response{}))
+	}
+	errorStatuses := []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError}
+	if admin {
+		errorStatuses = append(errorStatuses, http.StatusForbidden)
+		operation.Security = []map[string][]string{{"adminKey": {}}}
+	} else if versioned {
+		errorStatuses = append(errorStatuses, http.StatusTooManyRequests)
+		operation.Security = []map[string][]string{{"apiKey": {}}}
+	}
+	if doc.notFound {
+		errorStatuses = append(errorStatuses, http.StatusNotFound)
+	}
+	if hasRegion {
+		errorStatuses = append(errorStatuses, http.StatusServiceUnavailable)
+	}
+	if versioned {
+		for _, errorStatus := range errorStatuses {
+			operation.Responses[strconv.Itoa(errorStatus)] = openAPIResponse{
+				Description: http.StatusText(errorStatus),
+				Content:     map[string]openAPIMediaType{"application/json": {Schema: errorSchema}},
+			}
+		}
+	}
+	return operation
+}
+
+// schema describes the json encoding of the type, named structs are added to the components and referenced
+func (d *openAPIDocument) schema(t reflect.Type) *openAPISchema {
+	if t == reflect.TypeOf(time.Time{}) {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := d.schema(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		if _, exists := d.Components.Schemas[t.Name()]; !exists {
+			// reserved before describing the fields so recursive types end
+			d.Components.Schemas[t.Name()] = &openAPISchema{}
+			*d.Components.Schemas[t.Name()] = *d.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	return &openAPISchema{}
+}
+
+func (d *openAPIDocument) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	d.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields adds the json fields of the struct, the fields of embedded structs are promoted unless shadowed
+func (d *openAPIDocument) addFields(schema *openAPISchema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" {
+			embedded = append(embedded, field.Type)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		name, options := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, options = tag[:comma], tag[comma+1:]
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, exists := schema.Properties[name]; exists {
+			continue
+		}
+		schema.Properties[name] = d.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	for _, embeddedType := range embedded {
+		if embeddedType.Kind() == reflect.Ptr {
+			embeddedType = embeddedType.Elem()
+		}
+		d.addFields(schema, embeddedType)
+	}
+}
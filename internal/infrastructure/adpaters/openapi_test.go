@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	router := NewRouter(RitoHandler{})
+
+	t.Run("Test every route of the router is documented", func(t *testing.T) {
+		_, undocumented := newOpenAPIDocument(router.Routes())
+		assert.Empty(t, undocumented, "add the routes to routeDocs")
+	})
+	t.Run("Test every documented route exists in the router", func(t *testing.T) {
+		registered := make(map[string]bool)
+		for _, route := range router.Routes() {
+			registered[route.Method+" "+route.Path] = true
+		}
+		for key := range routeDocs {
+			assert.True(t, registered[key], "%s is documented but not routed", key)
+		}
+	})
+	t.Run("Test the document is served with the paths, parameters and models", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var document openAPIDocument
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &document))
+		assert.Equal(t, "3.0.3", document.OpenAPI)
+		for _, route := range router.Routes() {
+			path, _ := openAPIPath(route.Path)
+			assert.Contains(t, document.Paths[path], strings.ToLower(route.Method), route.Path)
+		}
+
+		liveGame := document.Paths["/api/v2/regions/{region}/summoners/{summoner}/live-game"]["get"]
+		assert.Len(t, liveGame.Parameters, 2)
+		assert.Contains(t, liveGame.Responses, "503")
+		assert.Equal(t, "#/components/schemas/Match", liveGame.Responses["200"].Content["application/json"].Schema.Properties["data"].Ref)
+		assert.Equal(t, "#/components/schemas/ErrorEnvelope", liveGame.Responses["400"].Content["application/json"].Schema.Ref)
+
+		archivedGame := document.Components.Schemas["ArchivedGame"]
+		assert.Contains(t, archivedGame.Properties, "summoners", "the fields of the embedded match are promoted")
+		assert.Equal(t, "date-time", archivedGame.Properties["archived_at"].Format)
+		assert.NotContains(t, document.Components.Schemas["LadderEntry"].Required, "position")
+	})
+	t.Run("Test the docs page loads the document", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "/api/openapi.json")
+		assert.NotContains(t, recorder.Body.String(), "<script src=", "the page loads no third party script")
+	})
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter keeps a token bucket per api client. Each bucket holds up to a minute of requests and is refilled
+// continuously, so a client can burst its whole quota and then goes on at its requests per minute.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// rateLimit is the state of a bucket after taking a request from it
+type rateLimit struct {
+	allowed   bool
+	limit     int
+	remaining int
+	// reset is the time until the bucket is full again
+	reset time.Duration
+	// retryAfter is the time until the next request is allowed, zero when this one was
+	retryAfter time.Duration
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// take spends a token of the client bucket when there is one
+func (l *RateLimiter) take(clientId string, requestsPerMinute int) rateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	capacity := float64(requestsPerMinute)
+	perSecond := capacity / 60
+	bucket, exists := l.buckets[clientId]
+	if !exists {
+		bucket = &tokenBucket{tokens: capacity, updatedAt: now}
+		l.buckets[clientId] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*perSecond)
+	bucket.updatedAt = now
+
+	limit := rateLimit{limit: requestsPerMinute}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		limit.allowed = true
+	} else {
+		limit.retryAfter = seconds((1 - bucket.tokens) / perSecond)
+	}
+	limit.remaining = int(bucket.tokens)
+	limit.reset = seconds((capacity - bucket.tokens) / perSecond)
+	return limit
+}
+
+// forget drops the bucket of a revoked client
+func (l *RateLimiter) forget(clientId string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.buckets, clientId)
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
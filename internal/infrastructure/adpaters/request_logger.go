@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const (
+	requestIdHeader = "X-Request-ID"
+	// maxRequestIdLength bounds the ids taken from the clients, longer ones are replaced
+	maxRequestIdLength = 128
+)
+
+// logRequests carries the X-Request-ID of the request, or a new one, in the request context so the services and
+// the provider log with it, answers it back and logs every request once answered
+func (handler RitoHandler) logRequests(c *gin.Context) {
+	requestId := c.GetHeader(requestIdHeader)
+	if !isValidRequestId(requestId) {
+		requestId = logging.NewRequestId()
+	}
+	logger := handler.Logger
+	if logger == nil {
+		logger = logging.Default()
+	}
+	ctx := logging.WithRequestId(logging.NewContext(c.Request.Context(), logger), requestId)
+	c.Request = c.Request.WithContext(ctx)
+	c.Header(requestIdHeader, requestId)
+
+	start := time.Now()
+	c.Next()
+
+	// the context logger may have got more fields, like the api client, while handling the request
+	entry := logging.FromContext(c.Request.Context()).With(
+		"method", c.Request.Method,
+		"path", c.Request.URL.Path,
+		"status", c.Writer.Status(),
+		"elapsed", time.Since(start).String(),
+	)
+	switch {
+	case c.Writer.Status() >= http.StatusInternalServerError:
+		entry.Error("request answered")
+	case c.Writer.Status() >= http.StatusBadRequest:
+		entry.Warn("request answered")
+	default:
+		entry.Info("request answered")
+	}
+}
+
+// observeRequests records the requests by route, the requests without route share the unmatched one
+func (handler RitoHandler) observeRequests(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	handler.Metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+}
+
+// isValidRequestId only accepts printable ascii without spaces, so the ids cannot forge log lines
+func isValidRequestId(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+	for _, char := range requestId {
+		if char <= ' ' || char > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,31 @@
 package infrastructure
 
+import "github.com/emipochettino/loleros-api/internal/domain"
+
 type Response struct {
 	Msg string `json:"msg"`
+	// Fields are the invalid fields of a bad request
+	Fields []FieldError `json:"fields,omitempty"`
+	// Notices are the ongoing rito incidents and maintenances of the region when the upstream failed
+	Notices []domain.PlatformNotice `json:"notices,omitempty"`
+}
+
+// DataEnvelope wraps every successful v2 response
+type DataEnvelope struct {
+	Data interface{} `json:"data"`
+}
+
+// ErrorEnvelope wraps every failed v2 response
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	// Code is a stable identifier of the kind of error, the message is meant for humans
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Fields are the invalid fields of a bad request
+	Fields []FieldError `json:"fields,omitempty"`
+	// Notices are the ongoing rito incidents and maintenances of the region when the upstream failed
+	Notices []domain.PlatformNotice `json:"notices,omitempty"`
 }
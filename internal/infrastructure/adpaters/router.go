@@ -1,15 +1,110 @@
 package infrastructure
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/metrics"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
 
 func NewRouter(ritoHandler RitoHandler) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
-	router := gin.Default()
+	// the requests are logged by logRequests instead of the gin logger
+	router := gin.New()
+	if ritoHandler.RateLimiter == nil {
+		ritoHandler.RateLimiter = NewRateLimiter()
+	}
+	if ritoHandler.Metrics == nil {
+		ritoHandler.Metrics = metrics.NewRegistry()
+	}
+	router.Use(ritoHandler.logRequests, ritoHandler.traceRequests, ritoHandler.observeRequests, gin.Recovery())
 
-	v1 := router.Group("/api/v1")
+	router.GET("/metrics", gin.WrapH(ritoHandler.Metrics.Handler()))
+	// the probes are left out of the api key authentication, the orchestrator calls them
+	router.GET("/healthz", ritoHandler.Liveness)
+	router.GET("/readyz", ritoHandler.Readiness)
+
+	v1 := router.Group("/api/v1", ritoHandler.authenticate, ritoHandler.validateRegionParam)
 	{
 		v1.GET("/ping", ritoHandler.Ping)
+		v1.GET("/health", ritoHandler.Health)
 		v1.GET("/rito/match", ritoHandler.FindMatchInfoByRegionAndSummoner)
+		v1.GET("/rito/match/stream", ritoHandler.StreamMatchByRegionAndSummoner)
+		v1.GET("/rito/featured", ritoHandler.FindFeaturedMatchesByRegion)
+		v1.GET("/watchlist", ritoHandler.FindWatchedSummoners)
+		v1.POST("/watchlist", ritoHandler.Watch)
+		v1.DELETE("/watchlist/:region/:summoner_id", ritoHandler.Unwatch)
+		v1.GET("/watchlist/deliveries", ritoHandler.FindWebhookDeliveries)
+		v1.POST("/rank-history/tracked", ritoHandler.TrackRankHistory)
+		v1.GET("/rank-history/:region/:summoner_id", ritoHandler.FindRankHistory)
+		v1.GET("/archived-games", ritoHandler.FindArchivedGames)
+		v1.GET("/archived-games/:region/:game_id", ritoHandler.FindArchivedGame)
+		v1.POST("/groups", ritoHandler.CreateGroup)
+		v1.GET("/groups/:group_id", ritoHandler.FindGroup)
+		v1.POST("/groups/:group_id/members", ritoHandler.AddGroupMember)
+		v1.DELETE("/groups/:group_id/members/:region/:summoner_id", ritoHandler.RemoveGroupMember)
+		v1.GET("/groups/:group_id/leaderboard", ritoHandler.FindGroupLeaderboard)
+		v1.GET("/ladders/:region/:queue/apex/:tier", ritoHandler.FindApexLadder)
+		v1.GET("/ladders/:region/:queue/entries/:tier/:division", ritoHandler.FindLeagueEntries)
+		v1.GET("/ladders/:region/:queue/standing/:summoner_id", ritoHandler.FindLadderStanding)
+		v1.GET("/status/:region", ritoHandler.FindPlatformStatus)
+		v1.GET("/clash/scout", ritoHandler.ScoutClashTeam)
+		v1.GET("/clash/tournaments", ritoHandler.FindClashTournaments)
+	}
+
+	// v2 answers every resource in a DataEnvelope and every error in an ErrorEnvelope
+	v2 := router.Group("/api/v2", ritoHandler.authenticate, ritoHandler.validateRegionParam)
+	{
+		v2.GET("/health", ritoHandler.v2(http.StatusOK, ritoHandler.HealthV2))
+
+		region := v2.Group("/regions/:region")
+		region.GET("/status", ritoHandler.v2(http.StatusOK, ritoHandler.FindPlatformStatusV2))
+		region.GET("/featured-games", ritoHandler.v2(http.StatusOK, ritoHandler.FindFeaturedMatchesV2))
+		region.GET("/archived-games/:game_id", ritoHandler.v2(http.StatusOK, ritoHandler.FindArchivedGameV2))
+		region.GET("/clash/tournaments", ritoHandler.v2(http.StatusOK, ritoHandler.FindClashTournamentsV2))
+		region.GET("/ladders/:queue/apex/:tier", ritoHandler.v2(http.StatusOK, ritoHandler.FindApexLadderV2))
+		region.GET("/ladders/:queue/tiers/:tier/divisions/:division", ritoHandler.v2(http.StatusOK, ritoHandler.FindLeagueEntriesV2))
+		region.GET("/ladders/:queue/standings/:summoner", ritoHandler.v2(http.StatusOK, ritoHandler.FindLadderStandingV2))
+
+		// gin needs the same wildcard name for every summoner route, it is a name or an id depending on the resource
+		summoners := region.Group("/summoners/:summoner")
+		summoners.GET("", ritoHandler.v2(http.StatusOK, ritoHandler.FindSummonerV2))
+		summoners.GET("/live-game", ritoHandler.v2(http.StatusOK, ritoHandler.FindLiveGameV2))
+		summoners.GET("/clash-team", ritoHandler.v2(http.StatusOK, ritoHandler.ScoutClashTeamV2))
+		summoners.GET("/leagues", ritoHandler.v2(http.StatusOK, ritoHandler.FindLeaguesV2))
+		summoners.GET("/rank-history", ritoHandler.v2(http.StatusOK, ritoHandler.FindRankHistoryV2))
+		summoners.GET("/archived-games", ritoHandler.v2(http.StatusOK, ritoHandler.FindArchivedGamesV2))
+
+		v2.POST("/tracked-summoners", ritoHandler.v2(http.StatusCreated, ritoHandler.TrackRankHistoryV2))
+		v2.GET("/watchlist", ritoHandler.v2(http.StatusOK, ritoHandler.FindWatchedSummonersV2))
+		v2.POST("/watchlist", ritoHandler.v2(http.StatusCreated, ritoHandler.WatchV2))
+		v2.DELETE("/watchlist/:region/:summoner", ritoHandler.v2(http.StatusNoContent, ritoHandler.UnwatchV2))
+		v2.GET("/watchlist/deliveries", ritoHandler.v2(http.StatusOK, ritoHandler.FindWebhookDeliveriesV2))
+		v2.POST("/groups", ritoHandler.v2(http.StatusCreated, ritoHandler.CreateGroupV2))
+		v2.GET("/groups/:group_id", ritoHandler.v2(http.StatusOK, ritoHandler.FindGroupV2))
+		v2.POST("/groups/:group_id/members", ritoHandler.v2(http.StatusOK, ritoHandler.AddGroupMemberV2))
+		v2.DELETE("/groups/:group_id/members/:region/:summoner", ritoHandler.v2(http.StatusOK, ritoHandler.RemoveGroupMemberV2))
+		v2.GET("/groups/:group_id/leaderboard", ritoHandler.v2(http.StatusOK, ritoHandler.FindGroupLeaderboardV2))
+	}
+
+	admin := router.Group("/api/admin", ritoHandler.authenticateAdmin)
+	{
+		admin.POST("/api-clients", ritoHandler.v2(http.StatusCreated, ritoHandler.IssueAPIClient))
+		admin.GET("/api-clients", ritoHandler.v2(http.StatusOK, ritoHandler.FindAPIClients))
+		admin.DELETE("/api-clients/:client_id", ritoHandler.v2(http.StatusNoContent, ritoHandler.RevokeAPIClient))
+	}
+
+	// the document is built once every route is registered, including its own
+	var openAPI *openAPIDocument
+	router.GET("/api/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, openAPI)
+	})
+	router.GET("/api/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+	})
+	openAPI, undocumented := newOpenAPIDocument(router.Routes())
+	for _, route := range undocumented {
+		logging.Default().Warn("the route is missing from the openapi document", "route", route)
 	}
 
 	return router
@@ -0,0 +1,814 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/fakerito"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/metrics"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/providers"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindMatchInfoByRegionAndSummonerEndToEnd(t *testing.T) {
+	tests := []struct {
+		name           string
+		region         string
+		scenarios      []fakerito.Scenario
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:           "Test find match successfully",
+			region:         "euw1",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find match after a rate limit burst",
+			region:         "euw1",
+			scenarios:      []fakerito.Scenario{fakerito.RateLimitBurst("/lol/summoner/v4/summoners/by-name", 1, time.Second)},
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find match with an expired key",
+			region:         "euw1",
+			scenarios:      []fakerito.Scenario{fakerito.ExpiredKey()},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "rito token can be expired",
+		}, {
+			name:           "Test find match when the spectator api fails",
+			region:         "euw1",
+			scenarios:      []fakerito.Scenario{fakerito.ServerErrors("/lol/spectator", http.StatusServiceUnavailable, 1)},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "uups, something went wrong",
+		}, {
+			name:           "Test find match with an unknown region",
+			region:         "unknown",
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "The field region is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newEndToEndRouter(t, fakerito.New(
+				"../providers/jsons",
+				fakerito.WithKeys("valid_token"),
+				fakerito.WithScenarios(tt.scenarios...),
+			))
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(
+				http.MethodGet,
+				"/api/v1/rito/match?region="+tt.region+"&summoner_name=xNibe",
+				nil,
+			)
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedStatus != http.StatusOK {
+				var response Response
+				assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedMsg, response.Msg)
+				return
+			}
+			var match domain.Match
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &match))
+			assert.Len(t, match.Summoners, 10)
+		})
+	}
+}
+
+func TestPlatformStatusEndToEnd(t *testing.T) {
+	incidents := `{"id": "EUW1", "name": "EU West", "maintenances": [], "incidents": [{
+		"id": 7001,
+		"incident_severity": "critical",
+		"titles": [{"locale": "en_US", "content": "Spectator unavailable"}],
+		"updates": [],
+		"created_at": "2020-11-12T10:00:00.000000+00:00",
+		"platforms": ["windows"]
+	}]}`
+
+	t.Run("Test find the platform status of a region", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/status/euw1", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var status domain.PlatformStatus
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+		assert.Equal(t, "EU West", status.Name)
+		assert.Empty(t, status.Incidents)
+		assert.Len(t, status.Maintenances, 1)
+		assert.Equal(t, "Scheduled patch maintenance", status.Maintenances[0].Title)
+		assert.Equal(t, []string{"Ranked queues will be disabled during the patch."}, status.Maintenances[0].Updates)
+	})
+	t.Run("Test a failing match lookup includes the ongoing incidents of the region", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New(
+			"../providers/jsons",
+			fakerito.WithScenarios(
+				fakerito.ServerErrors("/lol/spectator", http.StatusServiceUnavailable, 1),
+				fakerito.Scenario{
+					PathPrefix: "/lol/status",
+					Steps:      []fakerito.Step{{Status: http.StatusOK, Body: incidents}},
+				},
+			),
+		))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rito/match?region=euw1&summoner_name=xNibe", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		var response Response
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Len(t, response.Notices, 1)
+		assert.Equal(t, "critical", response.Notices[0].Severity)
+		assert.Equal(t, "Spectator unavailable", response.Notices[0].Title)
+	})
+	t.Run("Test the incidents are included while the region circuit breaker is open", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New(
+			"../providers/jsons",
+			fakerito.WithScenarios(
+				fakerito.ServerErrors("/lol/spectator", http.StatusServiceUnavailable, -1),
+				// the status is not cached while it fails, so it is asked again once the breaker is open
+				fakerito.Scenario{
+					PathPrefix: "/lol/status",
+					Steps: []fakerito.Step{
+						{Status: http.StatusInternalServerError, Times: 5},
+						{Status: http.StatusOK, Body: incidents, Times: -1},
+					},
+				},
+			),
+		))
+
+		var recorder *httptest.ResponseRecorder
+		for i := 0; i < 6; i++ {
+			recorder = httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/summoners/xNibe/live-game", nil))
+		}
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		var envelope ErrorEnvelope
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+		assert.Equal(t, "upstream_unavailable", envelope.Error.Code)
+		assert.Len(t, envelope.Error.Notices, 1)
+		assert.Equal(t, "Spectator unavailable", envelope.Error.Notices[0].Title)
+	})
+	t.Run("Test the incidents are not looked up when rito is not failing", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New(
+			"../providers/jsons",
+			fakerito.WithScenarios(
+				fakerito.Scenario{
+					PathPrefix: "/lol/summoner",
+					Steps:      []fakerito.Step{{Status: http.StatusForbidden, Times: -1}},
+				},
+				fakerito.Scenario{
+					PathPrefix: "/lol/status",
+					Steps:      []fakerito.Step{{Status: http.StatusOK, Body: incidents, Times: -1}},
+				},
+			),
+		))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rito/match?region=euw1&summoner_name=xNibe", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		var response Response
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+		assert.Equal(t, "rito token can be expired", response.Msg)
+		assert.Empty(t, response.Notices)
+	})
+}
+
+func TestFeaturedMatchesEndToEnd(t *testing.T) {
+	t.Run("Test the featured games are enriched with the participant ranks", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rito/featured?region=euw1", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var featured domain.FeaturedMatches
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &featured))
+		assert.EqualValues(t, 300, featured.RefreshIntervalSeconds)
+		assert.Len(t, featured.Matches, 2)
+		for _, match := range featured.Matches {
+			assert.Len(t, match.Summoners, 10)
+			assert.NotEmpty(t, match.Summoners[0].Leagues)
+		}
+	})
+}
+
+func TestClashEndToEnd(t *testing.T) {
+	t.Run("Test scout the clash team of a player ordered by position", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clash/scout?region=euw1&summoner_name=xNibe", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var team domain.ClashTeam
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &team))
+		assert.Equal(t, "Los Loleros", team.Name)
+		var positions []string
+		for _, player := range team.Players {
+			positions = append(positions, player.Position)
+			assert.NotEmpty(t, player.Leagues)
+			// the fixture only has two masteries
+			assert.Len(t, player.TopChampions, 2)
+		}
+		assert.Equal(t, []string{"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY"}, positions)
+		assert.EqualValues(t, 120, team.Players[0].TopChampions[0].ChampionId)
+	})
+	t.Run("Test scout a player without clash team", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New(
+			"../providers/jsons",
+			fakerito.WithScenarios(fakerito.Scenario{
+				PathPrefix: "/lol/clash/v1/players",
+				Steps:      []fakerito.Step{{Status: http.StatusOK, Body: "[]"}},
+			}),
+		))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clash/scout?region=euw1&summoner_name=xNibe", nil))
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+	t.Run("Test find the clash tournaments", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/clash/tournaments?region=euw1", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var tournaments []domain.ClashTournament
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &tournaments))
+		assert.Len(t, tournaments, 1)
+		assert.Equal(t, time.Date(2020, 11, 14, 20, 0, 0, 0, time.UTC), tournaments[0].Schedule[0].StartTime)
+	})
+}
+
+func TestArchivedGamesEndToEnd(t *testing.T) {
+	t.Run("Test looked up games are archived once and can be fetched", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+		for i := 0; i < 2; i++ {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rito/match?region=euw1&summoner_name=xNibe", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodGet,
+			"/api/v1/archived-games?region=euw1&summoner_id=flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX",
+			nil,
+		))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var games []domain.ArchivedGame
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &games))
+		assert.Len(t, games, 1)
+		assert.EqualValues(t, 3620211084, games[0].GameId)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/archived-games/euw1/1", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestGroupLeaderboardEndToEnd(t *testing.T) {
+	t.Run("Test group members are ranked by solo queue standing", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/groups", strings.NewReader(`{"name":"loleros"}`)))
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var group domain.Group
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &group))
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodPost,
+			fmt.Sprintf("/api/v1/groups/%d/members", group.Id),
+			strings.NewReader(`{"region":"euw1","summoner_name":"xNibe"}`),
+		))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/groups/%d/leaderboard", group.Id), nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var leaderboard domain.Leaderboard
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &leaderboard))
+		assert.Len(t, leaderboard.Entries, 1)
+		assert.Equal(t, 1, leaderboard.Entries[0].Position)
+		assert.Equal(t, "DIAMOND", leaderboard.Entries[0].SoloQueue.Tier)
+		assert.Equal(t, 1047, leaderboard.Entries[0].GamesPlayed)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/groups/999/leaderboard", nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestRankHistoryEndToEnd(t *testing.T) {
+	summonerId := "flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX"
+	t.Run("Test a tracked summoner has its first snapshot in the history", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodPost,
+			"/api/v1/rank-history/tracked",
+			strings.NewReader(`{"region":"euw1","summoner_name":"xNibe"}`),
+		))
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+		var tracked domain.TrackedSummoner
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &tracked))
+		assert.Equal(t, summonerId, tracked.SummonerId)
+		assert.Equal(t, "xNibe", tracked.SummonerName)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rank-history/euw1/"+summonerId, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var history []domain.RankHistoryPoint
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+		assert.Len(t, history, 1)
+		assert.Equal(t, "DIAMOND", history[0].Tier)
+		assert.Equal(t, 81, history[0].LeaguePoints)
+		assert.Equal(t, 0, history[0].LPChange)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rank-history/euw1/"+summonerId+"?queue=RANKED_FLEX_SR", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &history))
+		assert.Len(t, history, 1)
+		assert.Equal(t, "GOLD", history[0].Tier)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/rank-history/euw1/"+summonerId+"?days=0", nil))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+	t.Run("Test v2 tracks a summoner and answers its history in the envelope", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodPost,
+			"/api/v2/tracked-summoners",
+			strings.NewReader(`{"region":"euw1","summoner_name":"xNibe"}`),
+		))
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/summoners/"+summonerId+"/rank-history", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var envelope struct {
+			Data []domain.RankHistoryPoint `json:"data"`
+		}
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+		assert.Len(t, envelope.Data, 1)
+		assert.Equal(t, summonerId, envelope.Data[0].SummonerId)
+		assert.Equal(t, domain.SoloQueueType, envelope.Data[0].QueueType)
+	})
+	t.Run("Test v2 does not track an unknown summoner", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons", fakerito.WithScenarios(fakerito.Scenario{
+			PathPrefix: "/lol/summoner/v4/summoners/by-name",
+			Steps:      []fakerito.Step{{Status: http.StatusNotFound, Times: -1}},
+		})))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(
+			http.MethodPost,
+			"/api/v2/tracked-summoners",
+			strings.NewReader(`{"region":"euw1","summoner_name":"nobody"}`),
+		))
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/summoners/nobody_id/rank-history", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"data": []}`, recorder.Body.String())
+	})
+}
+
+// newEndToEndRouter wires the whole api against the fake rito server
+func newEndToEndRouter(t *testing.T, fakeRito *fakerito.Server) *gin.Engine {
+	server := httptest.NewServer(fakeRito)
+	t.Cleanup(server.Close)
+
+	ritoProvider, err := providers.NewRitoProvider(
+		map[string]string{"euw1": server.URL},
+		"valid_token",
+		cache.New(time.Minute, time.Minute),
+		providers.WithRegionalHosts(map[string]string{"europe": server.URL}),
+	)
+	assert.Nil(t, err)
+	dir, err := ioutil.TempDir("", "storage")
+	assert.Nil(t, err)
+	store, err := storage.NewSQLiteStore(filepath.Join(dir, "test.db"))
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = store.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	return NewRouter(RitoHandler{
+		MatchService:       application.NewMatchService(ritoProvider, store, nil),
+		HealthService:      application.NewHealthService(ritoProvider, store),
+		GroupService:       application.NewGroupService(ritoProvider, store, store),
+		RankHistoryService: application.NewRankHistoryService(ritoProvider, store),
+		LadderService:      application.NewLadderService(ritoProvider),
+		StatusService:      application.NewStatusService(ritoProvider),
+		ClashService:       application.NewClashService(ritoProvider),
+		SummonerService:    application.NewSummonerService(ritoProvider),
+		Regions:            []string{"euw1"},
+	})
+}
+
+func TestV2EndToEnd(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		scenarios      []fakerito.Scenario
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Test find the live game of a summoner",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/summoners/xNibe/live-game",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find a summoner with its leagues",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/summoners/xNibe",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find a summoner by riot id",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/summoners/xNibe%23EUW",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find the leagues of a summoner id",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/summoners/flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX/leagues",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test find the ladder standing of a summoner across the apex tiers",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/ladders/RANKED_SOLO_5x5/standings/flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX",
+			expectedStatus: http.StatusOK,
+		}, {
+			name:           "Test create a group",
+			method:         http.MethodPost,
+			path:           "/api/v2/groups",
+			body:           `{"name": "loleros"}`,
+			expectedStatus: http.StatusCreated,
+		}, {
+			name:           "Test find the live game with an unknown region",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/unknown/summoners/xNibe/live-game",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_request",
+		}, {
+			name:           "Test find an archived game with an invalid id",
+			method:         http.MethodGet,
+			path:           "/api/v2/regions/euw1/archived-games/abc",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid_request",
+		}, {
+			name:           "Test find a group that does not exist",
+			method:         http.MethodGet,
+			path:           "/api/v2/groups/999",
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
+		}, {
+			name:   "Test find the live game of a summoner not in game",
+			method: http.MethodGet,
+			path:   "/api/v2/regions/euw1/summoners/xNibe/live-game",
+			scenarios: []fakerito.Scenario{{
+				PathPrefix: "/lol/spectator/v4/active-games",
+				Steps:      []fakerito.Step{{Status: http.StatusNotFound, Times: -1}},
+			}},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
+		}, {
+			name:   "Test find the live game of an unknown summoner",
+			method: http.MethodGet,
+			path:   "/api/v2/regions/euw1/summoners/nobody/live-game",
+			scenarios: []fakerito.Scenario{{
+				PathPrefix: "/lol/summoner/v4/summoners/by-name",
+				Steps:      []fakerito.Step{{Status: http.StatusNotFound, Times: -1}},
+			}},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   "not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newEndToEndRouter(t, fakerito.New("../providers/jsons", fakerito.WithScenarios(tt.scenarios...)))
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			if tt.expectedCode != "" {
+				var envelope ErrorEnvelope
+				assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+				assert.Equal(t, tt.expectedCode, envelope.Error.Code)
+				assert.NotEmpty(t, envelope.Error.Message)
+				return
+			}
+			var envelope map[string]json.RawMessage
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+			assert.Contains(t, envelope, "data")
+			assert.Len(t, envelope, 1)
+		})
+	}
+}
+
+func TestRequestValidationEndToEnd(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedFields []FieldError
+	}{
+		{
+			name:   "Test find match with an unknown region and an invalid name",
+			method: http.MethodGet,
+			path:   "/api/v1/rito/match?region=xx1&summoner_name=a",
+			expectedFields: []FieldError{
+				{Field: "region", Reason: "should be one of euw1"},
+				{Field: "summoner_name", Reason: "should have between 3 and 16 characters"},
+			},
+		}, {
+			name:   "Test find match without parameters",
+			method: http.MethodGet,
+			path:   "/api/v1/rito/match",
+			expectedFields: []FieldError{
+				{Field: "region", Reason: "is required"},
+				{Field: "summoner_name", Reason: "is required"},
+			},
+		}, {
+			name:   "Test watch a summoner with an invalid riot id",
+			method: http.MethodPost,
+			path:   "/api/v1/watchlist",
+			body:   `{"region": "euw1", "summoner_name": "Faker#K"}`,
+			expectedFields: []FieldError{
+				{Field: "summoner_name", Reason: "should have a tag between 3 and 5 characters"},
+			},
+		}, {
+			name:   "Test find the rank history of an unknown region",
+			method: http.MethodGet,
+			path:   "/api/v1/rank-history/xx1/summoner_id",
+			expectedFields: []FieldError{
+				{Field: "region", Reason: "should be one of euw1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeRito := fakerito.New("../providers/jsons")
+			router := newEndToEndRouter(t, fakeRito)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			var response Response
+			assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedFields, response.Fields)
+			assert.Empty(t, fakeRito.Requests(), "invalid requests should not reach rito")
+		})
+	}
+	t.Run("Test v2 answers the invalid fields in the error envelope", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/regions/xx1/summoners/xNibe/live-game", nil))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		var envelope ErrorEnvelope
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &envelope))
+		assert.Equal(t, "invalid_request", envelope.Error.Code)
+		assert.Equal(t, []FieldError{{Field: "region", Reason: "should be one of euw1"}}, envelope.Error.Fields)
+	})
+}
+
+func TestRequestIdEndToEnd(t *testing.T) {
+	t.Run("Test the request id is answered and in every log line, including the rito requests", func(t *testing.T) {
+		server := httptest.NewServer(fakerito.New("../providers/jsons"))
+		defer server.Close()
+		ritoProvider, err := providers.NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			cache.New(time.Minute, time.Minute),
+			providers.WithMiddlewares(providers.LoggingMiddleware()),
+		)
+		assert.Nil(t, err)
+		var out bytes.Buffer
+		router := NewRouter(RitoHandler{
+			SummonerService: application.NewSummonerService(ritoProvider),
+			Logger:          logging.New(&out, logging.LevelDebug, true),
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/summoners/xNibe", nil)
+		request.Header.Set(requestIdHeader, "test-request")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "test-request", recorder.Header().Get(requestIdHeader))
+		var messages []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var entry map[string]interface{}
+			assert.Nil(t, json.Unmarshal([]byte(line), &entry))
+			assert.Equal(t, "test-request", entry["request_id"])
+			messages = append(messages, entry["msg"].(string))
+		}
+		assert.Contains(t, messages, "rito request")
+		assert.Equal(t, "request answered", messages[len(messages)-1])
+	})
+
+	t.Run("Test an invalid request id is replaced", func(t *testing.T) {
+		router := NewRouter(RitoHandler{Logger: logging.New(ioutil.Discard, logging.LevelInfo, false)})
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil)
+		request.Header.Set(requestIdHeader, "forged\nline")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Len(t, recorder.Header().Get(requestIdHeader), 16)
+	})
+}
+
+func TestProbesEndToEnd(t *testing.T) {
+	t.Run("Test the liveness probe answers without an api key", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"msg": "ok"}`, recorder.Body.String())
+	})
+	t.Run("Test the readiness probe reports every dependency", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons", fakerito.WithKeys("valid_token")))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var readiness domain.Readiness
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &readiness))
+		assert.Equal(t, domain.ReadinessReady, readiness.Status)
+		for _, name := range []string{"rito_token", "cache", "circuit_breakers", "store"} {
+			assert.Equal(t, domain.CheckOk, readiness.Checks[name].Status, name)
+		}
+		assert.Equal(t, map[string]string{"euw1": "closed", "europe": "closed"}, readiness.Checks["circuit_breakers"].Details)
+	})
+	t.Run("Test the readiness probe fails with an expired key", func(t *testing.T) {
+		router := newEndToEndRouter(t, fakerito.New(
+			"../providers/jsons",
+			fakerito.WithKeys("valid_token"),
+			fakerito.WithScenarios(fakerito.ExpiredKey()),
+		))
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		var readiness domain.Readiness
+		assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &readiness))
+		assert.Equal(t, domain.ReadinessNotReady, readiness.Status)
+		assert.Equal(t, domain.CheckFailed, readiness.Checks["rito_token"].Status)
+		assert.Equal(t, "rito token can be expired", readiness.Checks["rito_token"].Message)
+	})
+}
+
+func TestMetricsEndToEnd(t *testing.T) {
+	t.Run("Test the api requests, rito requests, cache lookups and fan-out are exposed", func(t *testing.T) {
+		server := httptest.NewServer(fakerito.New("../providers/jsons"))
+		defer server.Close()
+		registry := metrics.NewRegistry()
+		ritoProvider, err := providers.NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			cache.New(time.Minute, time.Minute),
+			providers.WithMiddlewares(providers.MetricsMiddleware(registry.ObserveUpstream)),
+			providers.WithMetrics(registry),
+		)
+		assert.Nil(t, err)
+		router := NewRouter(RitoHandler{
+			MatchService:  application.NewMatchService(ritoProvider, nil, registry),
+			StatusService: application.NewStatusService(ritoProvider),
+			Metrics:       registry,
+		})
+
+		for i := 0; i < 2; i++ {
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/featured-games", nil))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		assert.Contains(t, body, `loleros_http_requests_total{method="GET",route="/api/v2/regions/:region/featured-games",status="200"} 2`)
+		assert.Contains(t, body, `loleros_rito_requests_total{endpoint="spectator-v4.featured-games",region="euw1",status="200"} 1`)
+		assert.Contains(t, body, `loleros_cache_lookups_total{prefix="featured_games",result="hit"} 1`)
+		assert.Contains(t, body, `loleros_cache_lookups_total{prefix="featured_games",result="miss"} 1`)
+		assert.Contains(t, body, `loleros_fan_out_goroutines_count{operation="featured_matches"} 2`)
+	})
+	t.Run("Test the requests rejected by the circuit breaker are not counted as rito requests", func(t *testing.T) {
+		server := httptest.NewServer(fakerito.New("../providers/jsons", fakerito.WithScenarios(fakerito.Scenario{
+			PathPrefix: "/lol/spectator/v4/featured-games",
+			Steps:      []fakerito.Step{{Status: http.StatusInternalServerError, Times: -1}},
+		})))
+		defer server.Close()
+		registry := metrics.NewRegistry()
+		ritoProvider, err := providers.NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			cache.New(time.Minute, time.Minute),
+			providers.WithMiddlewares(providers.MetricsMiddleware(registry.ObserveUpstream)),
+		)
+		assert.Nil(t, err)
+		router := NewRouter(RitoHandler{
+			MatchService: application.NewMatchService(ritoProvider, nil, registry),
+			Metrics:      registry,
+		})
+
+		// the fifth failure opens the breaker, the sixth request is rejected
+		for i := 0; i < 6; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v2/regions/euw1/featured-games", nil))
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		body := recorder.Body.String()
+		assert.Contains(t, body, `loleros_rito_requests_total{endpoint="spectator-v4.featured-games",region="euw1",status="500"} 5`)
+		assert.Contains(t, body, `loleros_rito_request_duration_seconds_count{endpoint="spectator-v4.featured-games",region="euw1"} 5`)
+		assert.Contains(t, body, `loleros_rito_circuit_breaker_rejections_total{endpoint="spectator-v4.featured-games",region="euw1"} 1`)
+		assert.NotContains(t, body, `status="0"`)
+	})
+}
+
+func TestTracingEndToEnd(t *testing.T) {
+	t.Run("Test the request span has the match, participant and rito spans as descendants", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		router := newEndToEndRouter(t, fakerito.New("../providers/jsons"))
+
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/rito/match?region=euw1&summoner_name=xNibe", nil)
+		request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		spans := make(map[string][]sdktrace.ReadOnlySpan)
+		for _, span := range recorder.Ended() {
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+			spans[span.Name()] = append(spans[span.Name()], span)
+		}
+		assert.Len(t, spans["GET /api/v1/rito/match"], 1)
+		assert.Len(t, spans["matchService.FindCurrentMatchByRegionAndSummonerName"], 1)
+		assert.Len(t, spans["enrich participant"], 10)
+		assert.Equal(t,
+			spans["GET /api/v1/rito/match"][0].SpanContext().SpanID(),
+			spans["matchService.FindCurrentMatchByRegionAndSummonerName"][0].Parent().SpanID(),
+		)
+		for _, participant := range spans["enrich participant"] {
+			assert.Equal(t,
+				spans["matchService.FindCurrentMatchByRegionAndSummonerName"][0].SpanContext().SpanID(),
+				participant.Parent().SpanID(),
+			)
+		}
+		assert.NotEmpty(t, spans["rito summoner-v4.by-id"])
+		assert.NotEmpty(t, spans["HTTP GET"])
+	})
+}
@@ -0,0 +1,104 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+// ServerConfig tunes the http server answering the api
+type ServerConfig struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	// WriteTimeout is disabled by default, it would cut the match streams
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is how long the requests in flight are waited for once the server is told to stop
+	ShutdownTimeout time.Duration
+}
+
+func DefaultServerConfig() ServerConfig {
+	// PORT is the variable gin listened on, it keeps working
+	port := "8080"
+	if value, exists := os.LookupEnv("PORT"); exists {
+		port = value
+	}
+	return ServerConfig{
+		Addr:              ":" + port,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   30 * time.Second,
+	}
+}
+
+// ServerConfigFromEnv overrides the default config with the HTTP_* env variables
+func ServerConfigFromEnv() (ServerConfig, error) {
+	config := DefaultServerConfig()
+	if value, exists := os.LookupEnv("HTTP_ADDR"); exists {
+		config.Addr = value
+	}
+	durations := map[string]*time.Duration{
+		"HTTP_READ_HEADER_TIMEOUT": &config.ReadHeaderTimeout,
+		"HTTP_READ_TIMEOUT":        &config.ReadTimeout,
+		"HTTP_WRITE_TIMEOUT":       &config.WriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        &config.IdleTimeout,
+		"HTTP_SHUTDOWN_TIMEOUT":    &config.ShutdownTimeout,
+	}
+	for name, duration := range durations {
+		value, exists := os.LookupEnv(name)
+		if !exists {
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf("invalid %s: %s", name, err)
+		}
+		*duration = parsed
+	}
+
+	return config, nil
+}
+
+func NewServer(handler http.Handler, config ServerConfig) *http.Server {
+	return &http.Server{
+		Addr:              config.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+}
+
+// Serve answers on the listener until ctx is done, then stops accepting connections and waits for the requests
+// in flight up to the shutdown timeout. It returns nil once every request was answered.
+// The streams only end when their subscriptions are closed, which the server OnShutdown hooks are meant for.
+func Serve(ctx context.Context, server *http.Server, listener net.Listener, shutdownTimeout time.Duration) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	logging.Default().Info("listening", "addr", listener.Addr().String())
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	logging.Default().Info("shutting down, draining the requests in flight", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		// the requests still in flight are cut
+		_ = server.Close()
+		return fmt.Errorf("the requests in flight were not answered in time: %s", err)
+	}
+	return nil
+}
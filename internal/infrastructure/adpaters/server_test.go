@@ -0,0 +1,107 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestServerConfigFromEnv(t *testing.T) {
+	t.Run("Test the server config is read from the env", func(t *testing.T) {
+		setEnv(t, "HTTP_ADDR", "127.0.0.1:9090")
+		setEnv(t, "HTTP_WRITE_TIMEOUT", "1m")
+		setEnv(t, "HTTP_SHUTDOWN_TIMEOUT", "5s")
+
+		config, err := ServerConfigFromEnv()
+		assert.Nil(t, err)
+		assert.Equal(t, "127.0.0.1:9090", config.Addr)
+		assert.Equal(t, time.Minute, config.WriteTimeout)
+		assert.Equal(t, 5*time.Second, config.ShutdownTimeout)
+		assert.Equal(t, DefaultServerConfig().ReadTimeout, config.ReadTimeout)
+	})
+	t.Run("Test an invalid timeout returns an error", func(t *testing.T) {
+		setEnv(t, "HTTP_READ_TIMEOUT", "soon")
+
+		_, err := ServerConfigFromEnv()
+		assert.EqualError(t, err, `invalid HTTP_READ_TIMEOUT: time: invalid duration "soon"`)
+	})
+}
+
+func TestServe(t *testing.T) {
+	t.Run("Test the requests in flight are answered before stopping", func(t *testing.T) {
+		server, address, started, release := blockingServer(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		listener, err := net.Listen("tcp", address)
+		assert.Nil(t, err)
+		go func() {
+			served <- Serve(ctx, server, listener, time.Second)
+		}()
+
+		answered := make(chan string, 1)
+		go func() {
+			response, err := http.Get("http://" + listener.Addr().String())
+			assert.Nil(t, err)
+			body, _ := ioutil.ReadAll(response.Body)
+			_ = response.Body.Close()
+			answered <- string(body)
+		}()
+		<-started
+		cancel()
+		// the server keeps waiting for the request in flight
+		select {
+		case <-served:
+			t.Fatal("the server stopped with a request in flight")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+
+		assert.Equal(t, "answered", <-answered)
+		assert.Nil(t, <-served)
+	})
+	t.Run("Test the requests in flight are cut after the shutdown timeout", func(t *testing.T) {
+		server, address, started, release := blockingServer(t)
+		defer close(release)
+		ctx, cancel := context.WithCancel(context.Background())
+		listener, err := net.Listen("tcp", address)
+		assert.Nil(t, err)
+		served := make(chan error, 1)
+		go func() {
+			served <- Serve(ctx, server, listener, 20*time.Millisecond)
+		}()
+
+		go func() {
+			_, _ = http.Get("http://" + listener.Addr().String())
+		}()
+		<-started
+		cancel()
+
+		assert.EqualError(t, <-served, "the requests in flight were not answered in time: context deadline exceeded")
+	})
+}
+
+// blockingServer answers once release is closed, started tells the request arrived
+func blockingServer(t *testing.T) (*http.Server, string, chan struct{}, chan struct{}) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("answered"))
+	})
+	config := DefaultServerConfig()
+	config.Addr = "127.0.0.1:0"
+	return NewServer(handler, config), config.Addr, started, release
+}
+
+func setEnv(t *testing.T, name string, value string) {
+	assert.Nil(t, os.Setenv(name, value))
+	t.Cleanup(func() {
+		_ = os.Unsetenv(name)
+	})
+}
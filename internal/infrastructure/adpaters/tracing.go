@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+var tracer = otel.Tracer("github.com/emipochettino/loleros-api/internal/infrastructure/adpaters")
+
+// traceRequests starts the span of every request, as a child of the traceparent of the caller when there is one,
+// and adds the trace id to the logs of the request
+func (handler RitoHandler) traceRequests(c *gin.Context) {
+	ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(c.Request.Method),
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPTargetKey.String(c.Request.URL.Path),
+			attribute.String("request_id", logging.RequestId(ctx)),
+		),
+	)
+	defer span.End()
+	if span.SpanContext().IsValid() {
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("trace_id", span.SpanContext().TraceID().String()))
+	}
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+
+	status := c.Writer.Status()
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
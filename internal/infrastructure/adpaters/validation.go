@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"strings"
+)
+
+// FieldError tells why a field of the request is invalid
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// validationError is answered as a bad request listing every invalid field
+type validationError struct {
+	fields []FieldError
+}
+
+func (e validationError) Error() string {
+	names := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		names = append(names, field.Field)
+	}
+	if len(names) == 1 {
+		return fmt.Sprintf("The field %s is invalid", names[0])
+	}
+	return fmt.Sprintf("The fields %s are invalid", strings.Join(names, ", "))
+}
+
+// validate returns a validationError with the failed checks, nil when every check passed
+func validate(checks ...*FieldError) error {
+	var fields []FieldError
+	for _, check := range checks {
+		if check != nil {
+			fields = append(fields, *check)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return validationError{fields: fields}
+}
+
+// checkRegion accepts the regions with a configured host, any region when there is no configuration
+func (handler RitoHandler) checkRegion(field string, region string) *FieldError {
+	if region == "" {
+		return &FieldError{Field: field, Reason: "is required"}
+	}
+	if len(handler.Regions) == 0 {
+		return nil
+	}
+	for _, configured := range handler.Regions {
+		if configured == region {
+			return nil
+		}
+	}
+	return &FieldError{
+		Field:  field,
+		Reason: fmt.Sprintf("should be one of %s", strings.Join(handler.Regions, ", ")),
+	}
+}
+
+func checkRequired(field string, value string) *FieldError {
+	if value == "" {
+		return &FieldError{Field: field, Reason: "is required"}
+	}
+	return nil
+}
+
+func checkSummonerName(field string, name string) *FieldError {
+	if name == "" {
+		return &FieldError{Field: field, Reason: "is required"}
+	}
+	if err := domain.ValidateSummonerName(name); err != nil {
+		return &FieldError{Field: field, Reason: err.Error()}
+	}
+	return nil
+}
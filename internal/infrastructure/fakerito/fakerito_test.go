@@ -0,0 +1,110 @@
+package fakerito
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const fixturesDir = "../providers/jsons"
+
+func TestFixtures(t *testing.T) {
+	tests := []struct {
+		path string
+		file string
+	}{
+		{path: "/riot/account/v1/accounts/by-riot-id/xNibe/EUW", file: "account_response.json"},
+		{path: "/lol/summoner/v4/summoners/by-name/xNibe", file: "summoner_response.json"},
+		{path: "/lol/summoner/v4/summoners/by-puuid/puuid", file: "summoner_response.json"},
+		{path: "/lol/summoner/v4/summoners/summoner_id", file: "summoner_response.json"},
+		{path: "/lol/league/v4/entries/by-summoner/summoner_id", file: "leagues_response.json"},
+		{path: "/lol/league/v4/entries/RANKED_SOLO_5x5/DIAMOND/I", file: "league_entries_response.json"},
+		{path: "/lol/league/v4/challengerleagues/by-queue/RANKED_SOLO_5x5", file: "challenger_league_response.json"},
+		{path: "/lol/league/v4/grandmasterleagues/by-queue/RANKED_SOLO_5x5", file: "grandmaster_league_response.json"},
+		{path: "/lol/league/v4/masterleagues/by-queue/RANKED_SOLO_5x5", file: "master_league_response.json"},
+		{path: "/lol/spectator/v4/active-games/by-summoner/summoner_id", file: "active_game_response.json"},
+		{path: "/lol/spectator/v4/featured-games", file: "featured_games_response.json"},
+		{path: "/lol/champion-mastery/v4/champion-masteries/by-summoner/summoner_id", file: "champion_masteries_response.json"},
+		{path: "/lol/clash/v1/players/by-summoner/summoner_id", file: "clash_players_response.json"},
+		{path: "/lol/clash/v1/players/by-puuid/puuid", file: "clash_players_response.json"},
+		{path: "/lol/clash/v1/teams/team_id", file: "clash_team_response.json"},
+		{path: "/lol/clash/v1/tournaments", file: "clash_tournaments_response.json"},
+		{path: "/lol/status/v4/platform-data", file: "platform_status_response.json"},
+		{path: "/lol/match/v5/matches/EUW1_1", file: "match_response.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run("Test "+tt.path+" is served from "+tt.file, func(t *testing.T) {
+			expected, err := ioutil.ReadFile(filepath.Join(fixturesDir, tt.file))
+			assert.Nil(t, err)
+
+			recorder := get(New(fixturesDir), tt.path, "")
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, string(expected), recorder.Body.String())
+		})
+	}
+	t.Run("Test a path without fixture is not found", func(t *testing.T) {
+		recorder := get(New(fixturesDir), "/lol/unknown/v1/resource", "")
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func TestScenarios(t *testing.T) {
+	tests := []struct {
+		name             string
+		steps            []Step
+		expectedStatuses []int
+	}{
+		{
+			name:             "Test a step without times is served once",
+			steps:            []Step{{Status: http.StatusInternalServerError}},
+			expectedStatuses: []int{500, 200, 200},
+		}, {
+			name:             "Test a step is served its times before the next one",
+			steps:            []Step{{Status: http.StatusServiceUnavailable, Times: 2}, {Status: http.StatusTooManyRequests}},
+			expectedStatuses: []int{503, 503, 429, 200},
+		}, {
+			name:             "Test a step with negative times is served forever",
+			steps:            []Step{{Status: http.StatusBadGateway}, {Status: http.StatusNotFound, Times: -1}},
+			expectedStatuses: []int{502, 404, 404, 404},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New(fixturesDir, WithScenarios(Scenario{PathPrefix: "/lol/summoner", Steps: tt.steps}))
+
+			var statuses []int
+			for range tt.expectedStatuses {
+				statuses = append(statuses, get(server, "/lol/summoner/v4/summoners/by-name/xNibe", "").Code)
+			}
+			assert.Equal(t, tt.expectedStatuses, statuses)
+			assert.Equal(t, http.StatusOK, get(server, "/lol/status/v4/platform-data", "").Code, "other paths are not scripted")
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	t.Run("Test a request without a valid key is rejected without using up the scripted steps", func(t *testing.T) {
+		server := New(fixturesDir, WithKeys("valid_token"), WithScenarios(ServerErrors("/lol/summoner", http.StatusInternalServerError, 1)))
+
+		assert.Equal(t, http.StatusForbidden, get(server, "/lol/summoner/v4/summoners/by-name/xNibe", "expired_token").Code)
+		assert.Equal(t, http.StatusForbidden, get(server, "/lol/summoner/v4/summoners/by-name/xNibe", "").Code)
+		assert.Equal(t, http.StatusInternalServerError, get(server, "/lol/summoner/v4/summoners/by-name/xNibe", "valid_token").Code)
+		assert.Equal(t, http.StatusOK, get(server, "/lol/summoner/v4/summoners/by-name/xNibe", "valid_token").Code)
+		assert.Len(t, server.Requests(), 4)
+	})
+}
+
+func get(server *Server, path string, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if len(token) > 0 {
+		request.Header.Set("X-Riot-Token", token)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	return recorder
+}
@@ -0,0 +1,63 @@
+package fakerito
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitBurst answers 429 with the rito rate limit headers to the next times requests
+func RateLimitBurst(pathPrefix string, times int, retryAfter time.Duration) Scenario {
+	return Scenario{
+		PathPrefix: pathPrefix,
+		Steps: []Step{{
+			Status: http.StatusTooManyRequests,
+			Headers: map[string]string{
+				"Retry-After":       strconv.Itoa(int(retryAfter.Seconds())),
+				"X-Rate-Limit-Type": "application",
+				"X-App-Rate-Limit":  "20:1,100:120",
+			},
+			Times: times,
+		}},
+	}
+}
+
+// ServerErrors answers the status, expected to be a 5xx, to the next times requests
+func ServerErrors(pathPrefix string, status int, times int) Scenario {
+	return Scenario{
+		PathPrefix: pathPrefix,
+		Steps:      []Step{{Status: status, Times: times}},
+	}
+}
+
+// Latency delays the next times requests before serving the fixtures
+func Latency(pathPrefix string, latency time.Duration, times int) Scenario {
+	return Scenario{
+		PathPrefix: pathPrefix,
+		Steps:      []Step{{Status: http.StatusOK, LatencyMillis: int(latency / time.Millisecond), Times: times}},
+	}
+}
+
+// ExpiredKey answers 403 to every request from now on, as rito does once the development key expires
+func ExpiredKey() Scenario {
+	return Scenario{
+		PathPrefix: "/",
+		Steps:      []Step{{Status: http.StatusForbidden, Times: -1}},
+	}
+}
+
+// LoadScenarios reads a json array of scenarios
+func LoadScenarios(path string) ([]Scenario, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var scenarios []Scenario
+	if err = json.Unmarshal(content, &scenarios); err != nil {
+		return nil, fmt.Errorf("invalid scenarios file %s: %s", path, err)
+	}
+	return scenarios, nil
+}
@@ -0,0 +1,231 @@
+// Package fakerito is a fake rito api meant for integration tests and local development.
+//
+// Responses are read from the fixtures directory the provider tests use, providers/jsons, each rito resource
+// being served from its fixture file whatever the ids in the path. Scripted scenarios take precedence over fixtures.
+package fakerito
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fixture is the file serving the rito paths matching pattern, whose * match a single path segment
+type fixture struct {
+	pattern string
+	file    string
+}
+
+// fixtures are tried in order, the first matching pattern wins
+var fixtures = []fixture{
+	{pattern: "/riot/account/v1/accounts/by-riot-id/*/*", file: "account_response.json"},
+	{pattern: "/lol/summoner/v4/summoners/by-name/*", file: "summoner_response.json"},
+	{pattern: "/lol/summoner/v4/summoners/by-puuid/*", file: "summoner_response.json"},
+	{pattern: "/lol/summoner/v4/summoners/*", file: "summoner_response.json"},
+	{pattern: "/lol/league/v4/entries/by-summoner/*", file: "leagues_response.json"},
+	{pattern: "/lol/league/v4/entries/*/*/*", file: "league_entries_response.json"},
+	{pattern: "/lol/league/v4/challengerleagues/by-queue/*", file: "challenger_league_response.json"},
+	{pattern: "/lol/league/v4/grandmasterleagues/by-queue/*", file: "grandmaster_league_response.json"},
+	{pattern: "/lol/league/v4/masterleagues/by-queue/*", file: "master_league_response.json"},
+	{pattern: "/lol/spectator/v4/active-games/by-summoner/*", file: "active_game_response.json"},
+	{pattern: "/lol/spectator/v4/featured-games", file: "featured_games_response.json"},
+	{pattern: "/lol/champion-mastery/v4/champion-masteries/by-summoner/*", file: "champion_masteries_response.json"},
+	{pattern: "/lol/clash/v1/players/by-summoner/*", file: "clash_players_response.json"},
+	{pattern: "/lol/clash/v1/players/by-puuid/*", file: "clash_players_response.json"},
+	{pattern: "/lol/clash/v1/teams/*", file: "clash_team_response.json"},
+	{pattern: "/lol/clash/v1/tournaments", file: "clash_tournaments_response.json"},
+	{pattern: "/lol/status/v4/platform-data", file: "platform_status_response.json"},
+	{pattern: "/lol/match/v5/matches/*", file: "match_response.json"},
+}
+
+// Step is a scripted response. It is served Times times (once when zero, forever when negative)
+// before moving to the next step.
+type Step struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	// Body is written as is, when empty the fixture is served for 2xx steps and a rito error otherwise
+	Body          string `json:"body"`
+	LatencyMillis int    `json:"latency_ms"`
+	Times         int    `json:"times"`
+}
+
+// Scenario scripts the responses of the requests whose path starts with PathPrefix.
+// Once every step was served the requests go back to the fixtures.
+type Scenario struct {
+	PathPrefix string `json:"path_prefix"`
+	Steps      []Step `json:"steps"`
+}
+
+type scriptedScenario struct {
+	Scenario
+	served int
+}
+
+// next returns the step for the current request, false when the scenario is over
+func (s *scriptedScenario) next() (Step, bool) {
+	served := s.served
+	for _, step := range s.Steps {
+		if step.Times < 0 {
+			return step, true
+		}
+		times := step.Times
+		if times == 0 {
+			times = 1
+		}
+		if served < times {
+			s.served++
+			return step, true
+		}
+		served -= times
+	}
+	return Step{}, false
+}
+
+// Server is an http.Handler serving the fake rito api
+type Server struct {
+	fixturesDir string
+	keys        map[string]bool
+
+	mu        sync.Mutex
+	scenarios []*scriptedScenario
+	requests  []string
+}
+
+// Option customizes the server on creation
+type Option func(server *Server)
+
+// WithKeys makes the server answer 403 to requests without one of the keys
+func WithKeys(keys ...string) Option {
+	return func(server *Server) {
+		for _, key := range keys {
+			server.keys[key] = true
+		}
+	}
+}
+
+// WithScenarios scripts the given scenarios from the start
+func WithScenarios(scenarios ...Scenario) Option {
+	return func(server *Server) {
+		for _, scenario := range scenarios {
+			server.Script(scenario)
+		}
+	}
+}
+
+func New(fixturesDir string, options ...Option) *Server {
+	server := &Server{
+		fixturesDir: fixturesDir,
+		keys:        make(map[string]bool),
+	}
+	for _, option := range options {
+		option(server)
+	}
+	return server
+}
+
+// Script adds a scenario, the first scenario matching a request wins
+func (s *Server) Script(scenario Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenarios = append(s.scenarios, &scriptedScenario{Scenario: scenario})
+}
+
+// Reset removes the scenarios and the request log
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenarios = nil
+	s.requests = nil
+}
+
+// Requests returns the paths requested so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path)
+	if len(s.keys) > 0 && !s.keys[r.Header.Get("X-Riot-Token")] {
+		s.mu.Unlock()
+		// a rejected request does not use up the scripted steps
+		writeError(w, http.StatusForbidden, "Forbidden")
+		return
+	}
+	step, scripted := s.nextStep(r.URL.Path)
+	s.mu.Unlock()
+
+	if !scripted {
+		s.serveFixture(w, r.URL.Path, http.StatusOK)
+		return
+	}
+
+	if step.LatencyMillis > 0 {
+		time.Sleep(time.Duration(step.LatencyMillis) * time.Millisecond)
+	}
+	for name, value := range step.Headers {
+		w.Header().Set(name, value)
+	}
+	status := step.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	switch {
+	case len(step.Body) > 0:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(step.Body))
+	case status < http.StatusMultipleChoices:
+		s.serveFixture(w, r.URL.Path, status)
+	default:
+		writeError(w, status, http.StatusText(status))
+	}
+}
+
+func (s *Server) nextStep(requestPath string) (Step, bool) {
+	for _, scenario := range s.scenarios {
+		if !strings.HasPrefix(requestPath, scenario.PathPrefix) {
+			continue
+		}
+		if step, exists := scenario.next(); exists {
+			return step, true
+		}
+	}
+	return Step{}, false
+}
+
+func (s *Server) serveFixture(w http.ResponseWriter, requestPath string, status int) {
+	content, err := s.readFixture(requestPath)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Data not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(content)
+}
+
+func (s *Server) readFixture(requestPath string) ([]byte, error) {
+	cleaned := path.Clean("/" + requestPath)
+	for _, fixture := range fixtures {
+		if matched, _ := path.Match(fixture.pattern, cleaned); matched {
+			return ioutil.ReadFile(filepath.Join(s.fixturesDir, fixture.file))
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// writeError answers with the rito error body
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, `{"status":{"message":%q,"status_code":%d}}`, message, status)
+}
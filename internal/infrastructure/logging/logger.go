@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel accepts the level names in any case
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+// Logger writes a line per entry with the fields of the logger and the entry, as text or as json.
+// The loggers derived with With share the output of their parent.
+type Logger struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	level  Level
+	json   bool
+	fields []field
+	now    func() time.Time
+}
+
+type field struct {
+	key   string
+	value interface{}
+}
+
+func New(out io.Writer, level Level, json bool) *Logger {
+	return &Logger{out: out, mu: &sync.Mutex{}, level: level, json: json, now: time.Now}
+}
+
+// FromEnv writes to stderr at the LOG_LEVEL, info by default, in the LOG_FORMAT, text or json
+func FromEnv() (*Logger, error) {
+	level := LevelInfo
+	if name := os.Getenv("LOG_LEVEL"); len(name) > 0 {
+		var err error
+		if level, err = ParseLevel(name); err != nil {
+			return nil, err
+		}
+	}
+	format := os.Getenv("LOG_FORMAT")
+	if format != "" && format != "text" && format != "json" {
+		return nil, fmt.Errorf("unknown log format %q, it should be text or json", format)
+	}
+	return New(os.Stderr, level, format == "json"), nil
+}
+
+// With returns a logger adding the key value pairs to every entry
+func (l *Logger) With(keyValues ...interface{}) *Logger {
+	derived := *l
+	derived.fields = append(append([]field{}, l.fields...), pairs(keyValues)...)
+	return &derived
+}
+
+func (l *Logger) Debug(msg string, keyValues ...interface{}) {
+	l.log(LevelDebug, msg, keyValues)
+}
+
+func (l *Logger) Info(msg string, keyValues ...interface{}) {
+	l.log(LevelInfo, msg, keyValues)
+}
+
+func (l *Logger) Warn(msg string, keyValues ...interface{}) {
+	l.log(LevelWarn, msg, keyValues)
+}
+
+func (l *Logger) Error(msg string, keyValues ...interface{}) {
+	l.log(LevelError, msg, keyValues)
+}
+
+func (l *Logger) log(level Level, msg string, keyValues []interface{}) {
+	if level < l.level {
+		return
+	}
+	fields := append(append([]field{}, l.fields...), pairs(keyValues)...)
+	var line []byte
+	if l.json {
+		line = l.jsonLine(level, msg, fields)
+	} else {
+		line = l.textLine(level, msg, fields)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(line)
+}
+
+func (l *Logger) jsonLine(level Level, msg string, fields []field) []byte {
+	entry := map[string]interface{}{
+		"time":  l.now().UTC().Format(time.RFC3339Nano),
+		"level": level.String(),
+		"msg":   msg,
+	}
+	for _, f := range fields {
+		entry[f.key] = f.value
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]string{"level": level.String(), "msg": msg, "error": err.Error()})
+	}
+	return append(line, '\n')
+}
+
+func (l *Logger) textLine(level Level, msg string, fields []field) []byte {
+	var line strings.Builder
+	line.WriteString(l.now().UTC().Format(time.RFC3339))
+	line.WriteString(" ")
+	line.WriteString(strings.ToUpper(level.String()))
+	line.WriteString(" ")
+	line.WriteString(msg)
+	for _, f := range fields {
+		value := fmt.Sprint(f.value)
+		if strings.ContainsAny(value, " \"=") {
+			value = fmt.Sprintf("%q", value)
+		}
+		line.WriteString(" " + f.key + "=" + value)
+	}
+	line.WriteString("\n")
+	return []byte(line.String())
+}
+
+// pairs reads the key value pairs, a key without value is kept with an empty value
+func pairs(keyValues []interface{}) []field {
+	fields := make([]field, 0, (len(keyValues)+1)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		f := field{key: fmt.Sprint(keyValues[i])}
+		if i+1 < len(keyValues) {
+			f.value = keyValues[i+1]
+			if err, isErr := f.value.(error); isErr {
+				f.value = err.Error()
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger = New(os.Stderr, LevelInfo, false)
+)
+
+// SetDefault replaces the logger of the contexts without one
+func SetDefault(logger *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = logger
+}
+
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIdKey
+)
+
+// NewContext carries the logger in the context, so every layer handling the request logs with its fields
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the logger of the context, the default one when it has none
+func FromContext(ctx context.Context) *Logger {
+	if logger, exists := ctx.Value(loggerKey).(*Logger); exists {
+		return logger
+	}
+	return Default()
+}
+
+// WithRequestId carries the request id in the context and adds it to the logger of the context
+func WithRequestId(ctx context.Context, requestId string) context.Context {
+	ctx = context.WithValue(ctx, requestIdKey, requestId)
+	return NewContext(ctx, FromContext(ctx).With("request_id", requestId))
+}
+
+// RequestId returns the request id of the context, empty when it has none
+func RequestId(ctx context.Context) string {
+	requestId, _ := ctx.Value(requestIdKey).(string)
+	return requestId
+}
+
+// NewRequestId returns a random id for the requests without one
+func NewRequestId() string {
+	bytes := make([]byte, 8)
+	if _, err := rand.Read(bytes); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(bytes)
+}
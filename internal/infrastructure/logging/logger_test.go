@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	t.Run("Test text entries below the level are skipped", func(t *testing.T) {
+		var out bytes.Buffer
+		logger := newTestLogger(&out, LevelInfo, false)
+
+		logger.Debug("skipped")
+		logger.With("region", "euw1").Warn("rito answered", "status", 503, "err", errors.New("server error"))
+
+		assert.Equal(t, "2020-10-16T22:00:00Z WARN rito answered region=euw1 status=503 err=\"server error\"\n", out.String())
+	})
+
+	t.Run("Test json entries have the fields of the context logger", func(t *testing.T) {
+		var out bytes.Buffer
+		ctx := NewContext(context.Background(), newTestLogger(&out, LevelDebug, true))
+		ctx = WithRequestId(ctx, "test_id")
+
+		FromContext(ctx).Debug("rito request", "endpoint", "summoner-by-name")
+
+		var entry map[string]interface{}
+		assert.Nil(t, json.Unmarshal(out.Bytes(), &entry))
+		assert.Equal(t, map[string]interface{}{
+			"time":       "2020-10-16T22:00:00Z",
+			"level":      "debug",
+			"msg":        "rito request",
+			"request_id": "test_id",
+			"endpoint":   "summoner-by-name",
+		}, entry)
+		assert.Equal(t, "test_id", RequestId(ctx))
+	})
+}
+
+func newTestLogger(out *bytes.Buffer, level Level, json bool) *Logger {
+	logger := New(out, level, json)
+	logger.now = func() time.Time { return time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC) }
+	return logger
+}
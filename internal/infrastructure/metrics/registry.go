@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"errors"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/providers"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "loleros"
+
+// Registry has the metrics of the api, the rito calls and the services, exposed in the prometheus format
+type Registry struct {
+	registry         *prometheus.Registry
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+	upstreamRejected *prometheus.CounterVec
+	upstreamRetries  *prometheus.CounterVec
+	rateLimitWait    *prometheus.CounterVec
+	cacheLookups     *prometheus.CounterVec
+	fanOut           *prometheus.HistogramVec
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Requests answered by the api by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to answer the requests of the api by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rito_requests_total",
+			Help:      "Requests sent to rito by endpoint, region and status, the status is 0 when the request failed.",
+		}, []string{"endpoint", "region", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rito_request_duration_seconds",
+			Help:      "Time rito took to answer by endpoint and region.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint", "region"}),
+		upstreamRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rito_circuit_breaker_rejections_total",
+			Help:      "Requests not sent to rito because the circuit breaker of the region was open.",
+		}, []string{"endpoint", "region"}),
+		upstreamRetries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rito_retries_total",
+			Help:      "Requests retried because rito answered the rate limit was exceeded.",
+		}, []string{"endpoint", "region"}),
+		rateLimitWait: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rito_rate_limit_wait_seconds_total",
+			Help:      "Time waited before retrying the rate limited requests.",
+		}, []string{"endpoint", "region"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Cache lookups of the rito responses by key prefix and result, hit or miss.",
+		}, []string{"prefix", "result"}),
+		fanOut: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fan_out_goroutines",
+			Help:      "Goroutines started to enrich the participants of the matches by operation.",
+			Buckets:   []float64{1, 2, 5, 10, 20, 50, 100, 200},
+		}, []string{"operation"}),
+	}
+	r.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		r.httpRequests,
+		r.httpDuration,
+		r.upstreamRequests,
+		r.upstreamDuration,
+		r.upstreamRejected,
+		r.upstreamRetries,
+		r.rateLimitWait,
+		r.cacheLookups,
+		r.fanOut,
+	)
+	return r
+}
+
+// Handler answers the metrics in the prometheus text format
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a request of the api, the route is the path template so the ids do not add labels
+func (r *Registry) ObserveHTTPRequest(method string, route string, status int, elapsed time.Duration) {
+	r.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	r.httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveUpstream is the providers.MetricsObserver recording the rito requests.
+// The requests rejected by the circuit breaker never reached rito, they are counted apart.
+func (r *Registry) ObserveUpstream(call *providers.Call, status int, elapsed time.Duration, err error) {
+	if errors.As(err, &application.UpstreamUnavailableError{}) {
+		r.upstreamRejected.WithLabelValues(call.Endpoint, call.Region).Inc()
+		return
+	}
+	r.upstreamRequests.WithLabelValues(call.Endpoint, call.Region, strconv.Itoa(status)).Inc()
+	r.upstreamDuration.WithLabelValues(call.Endpoint, call.Region).Observe(elapsed.Seconds())
+}
+
+func (r *Registry) ObserveRetry(endpoint string, region string, wait time.Duration) {
+	r.upstreamRetries.WithLabelValues(endpoint, region).Inc()
+	r.rateLimitWait.WithLabelValues(endpoint, region).Add(wait.Seconds())
+}
+
+func (r *Registry) ObserveCacheLookup(prefix string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	r.cacheLookups.WithLabelValues(prefix, result).Inc()
+}
+
+func (r *Registry) ObserveFanOut(operation string, goroutines int) {
+	r.fanOut.WithLabelValues(operation).Observe(float64(goroutines))
+}
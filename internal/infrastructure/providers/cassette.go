@@ -0,0 +1,185 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+const (
+	CassetteModeRecord = "record"
+	CassetteModeReplay = "replay"
+)
+
+// query params never written to a cassette, request headers are not recorded at all so the token never leaks
+var scrubbedParams = []string{"api_key"}
+
+// Interaction is a recorded rito request and its response
+type Interaction struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body"`
+}
+
+// Cassette is the file format shared by the recording and replaying transports
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+func loadCassette(path string) (Cassette, error) {
+	var cassette Cassette
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cassette, err
+	}
+	if err = json.Unmarshal(content, &cassette); err != nil {
+		return cassette, fmt.Errorf("invalid cassette %s: %s", path, err)
+	}
+	return cassette, nil
+}
+
+// recordingTransport sends the requests to rito and appends every answer to the cassette file
+type recordingTransport struct {
+	next     http.RoundTripper
+	path     string
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecordingTransport records into path, keeping the interactions already in it
+func NewRecordingTransport(next http.RoundTripper, path string) (http.RoundTripper, error) {
+	cassette, err := loadCassette(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return &recordingTransport{next: next, path: path, cassette: cassette}, nil
+}
+
+func (t *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Method:  request.Method,
+		URL:     scrubURL(request.URL),
+		Status:  response.StatusCode,
+		Headers: response.Header,
+		Body:    string(body),
+	})
+	if err = t.save(); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (t *recordingTransport) save() error {
+	content, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, content, 0644)
+}
+
+// replayingTransport answers from a cassette without reaching rito.
+// Repeated requests to the same url get the recorded answers in order, the last one being repeated once exhausted.
+type replayingTransport struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	served       map[string]int
+}
+
+func NewReplayingTransport(path string) (http.RoundTripper, error) {
+	cassette, err := loadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	transport := &replayingTransport{
+		interactions: make(map[string][]Interaction),
+		served:       make(map[string]int),
+	}
+	for _, interaction := range cassette.Interactions {
+		key := interaction.Method + " " + interaction.URL
+		transport.interactions[key] = append(transport.interactions[key], interaction)
+	}
+	return transport, nil
+}
+
+func (t *replayingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	key := request.Method + " " + scrubURL(request.URL)
+
+	t.mu.Lock()
+	recorded, exists := t.interactions[key]
+	if !exists {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("no recorded interaction for %s", key)
+	}
+	index := t.served[key]
+	if index < len(recorded)-1 {
+		t.served[key]++
+	}
+	t.mu.Unlock()
+
+	interaction := recorded[index]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Headers.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Body))),
+		ContentLength: int64(len(interaction.Body)),
+		Request:       request,
+	}, nil
+}
+
+// NewCassetteClient wraps the client transport to record or replay the rito traffic
+func NewCassetteClient(client *http.Client, mode string, path string) (*http.Client, error) {
+	var transport http.RoundTripper
+	var err error
+	switch mode {
+	case CassetteModeRecord:
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		transport, err = NewRecordingTransport(next, path)
+	case CassetteModeReplay:
+		transport, err = NewReplayingTransport(path)
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: client.Timeout}, nil
+}
+
+func scrubURL(u *url.URL) string {
+	scrubbed := *u
+	query := scrubbed.Query()
+	for _, param := range scrubbedParams {
+		query.Del(param)
+	}
+	scrubbed.RawQuery = query.Encode()
+	scrubbed.User = nil
+	return scrubbed.String()
+}
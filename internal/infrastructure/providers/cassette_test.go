@@ -0,0 +1,51 @@
+package providers
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	t.Run("Test recorded rito answers are replayed without the token", func(t *testing.T) {
+		content, err := ioutil.ReadFile("jsons/summoner_response.json")
+		assert.Nil(t, err)
+		server := serverMock(
+			"/lol/summoner/v4/summoners/by-name/test_name",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(content)
+			})
+		dir, err := ioutil.TempDir("", "cassette")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "cassette.json")
+
+		client, err := NewCassetteClient(&http.Client{}, CassetteModeRecord, path)
+		assert.Nil(t, err)
+		recorder, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "secret_token", createEmptyCache(), WithClient(client))
+		assert.Nil(t, err)
+		recorded, err := recorder.FindSummonerByRegionAndName(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		server.Close()
+
+		cassette, err := ioutil.ReadFile(path)
+		assert.Nil(t, err)
+		assert.False(t, strings.Contains(string(cassette), "secret_token"))
+
+		client, err = NewCassetteClient(&http.Client{}, CassetteModeReplay, path)
+		assert.Nil(t, err)
+		replayer, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "other_token", createEmptyCache(), WithClient(client))
+		assert.Nil(t, err)
+		replayed, err := replayer.FindSummonerByRegionAndName(context.Background(), "test_region", "test_name")
+		assert.Nil(t, err)
+		assert.Equal(t, recorded, replayed)
+
+		_, err = replayer.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"github.com/emipochettino/loleros-api/internal/application"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	circuitBreakerThreshold = 5
+	circuitBreakerCooldown  = 30 * time.Second
+)
+
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half-open"
+)
+
+// circuitBreaker stops sending requests to a host after consecutive failures.
+// Once the cooldown is over a single probe is let through to decide whether the host recovered.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		state:     circuitClosed,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow tells whether a request can be sent, moving an open breaker to half-open when the cooldown is over
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// there is already a probe in flight
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// abandon forgets a request that tells nothing about the host, a probe abandoned while half-open lets the next one through
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitHalfOpen {
+		// the cooldown is still over since openedAt was not moved
+		b.state = circuitOpen
+	}
+}
+
+func (b *circuitBreaker) currentState() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// newCircuitBreakers gives each platform and regional host its own breaker,
+// so a regional host failing does not hold back the platform endpoints of its regions
+func newCircuitBreakers(host map[string]string, regionalHost map[string]string) map[string]*circuitBreaker {
+	breakers := make(map[string]*circuitBreaker, len(host)+len(regionalHost))
+	for name := range host {
+		breakers[name] = newCircuitBreaker(circuitBreakerThreshold, circuitBreakerCooldown)
+	}
+	for name := range regionalHost {
+		breakers[name] = newCircuitBreaker(circuitBreakerThreshold, circuitBreakerCooldown)
+	}
+	return breakers
+}
+
+// circuitBreakerMiddleware fails fast with an application.UpstreamUnavailableError while the breaker of the host is open.
+// Network errors and 5xx answers count as failures, requests cancelled by the caller do not, as the host was not at fault.
+// The calls bypassing the breaker are neither held back nor counted.
+func circuitBreakerMiddleware(breakers map[string]*circuitBreaker) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			breaker, exists := breakers[call.Host]
+			if !exists || call.BypassCircuitBreaker {
+				return next(call)
+			}
+			if !breaker.allow() {
+				return nil, application.UpstreamUnavailableError{Region: call.Region}
+			}
+
+			response, err := next(call)
+			if err != nil && call.Request.Context().Err() != nil {
+				breaker.abandon()
+				return response, err
+			}
+			breaker.record(err != nil || response.StatusCode >= http.StatusInternalServerError)
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"context"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	t.Run("Test rito is not called while the region circuit breaker is open", func(t *testing.T) {
+		requestNumber := 0
+		server := serverMock(
+			"/lol/summoner/v4/summoners/test_id",
+			func(w http.ResponseWriter, r *http.Request) {
+				requestNumber++
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+		defer server.Close()
+		provider, err := NewRitoProvider(
+			map[string]string{"test_region": server.URL},
+			"valid_token",
+			createEmptyCache(),
+		)
+		assert.Nil(t, err)
+
+		for i := 0; i < circuitBreakerThreshold; i++ {
+			_, err = provider.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
+			assert.NotNil(t, err)
+		}
+		_, err = provider.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
+		assert.EqualValues(t, application.UpstreamUnavailableError{Region: "test_region"}, err)
+		assert.Equal(t, circuitBreakerThreshold, requestNumber)
+		assert.Equal(t, map[string]string{"test_region": circuitOpen}, provider.CircuitBreakerStates())
+	})
+}
+
+func TestCircuitBreakerIgnoresCancelledRequests(t *testing.T) {
+	t.Run("Test requests cancelled by the caller do not open the circuit breaker", func(t *testing.T) {
+		release := make(chan struct{})
+		server := serverMock(
+			"/lol/summoner/v4/summoners/test_id",
+			func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			})
+		defer server.Close()
+		defer close(release)
+		provider, err := NewRitoProvider(
+			map[string]string{"test_region": server.URL},
+			"valid_token",
+			createEmptyCache(),
+		)
+		assert.Nil(t, err)
+
+		for i := 0; i < circuitBreakerThreshold; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			_, err = provider.FindSummonerByRegionAndId(ctx, "test_region", "test_id")
+			cancel()
+			assert.NotNil(t, err)
+		}
+		assert.Equal(t, map[string]string{"test_region": circuitClosed}, provider.CircuitBreakerStates())
+	})
+	t.Run("Test a probe cancelled while half-open lets the next one through", func(t *testing.T) {
+		now := time.Now()
+		breaker := newCircuitBreaker(1, time.Minute)
+		breaker.now = func() time.Time { return now }
+		breaker.record(true)
+
+		now = now.Add(time.Minute)
+		assert.True(t, breaker.allow())
+		breaker.abandon()
+		assert.Equal(t, circuitOpen, breaker.currentState())
+		assert.True(t, breaker.allow())
+	})
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Run("Test circuit breaker lets a single probe through after the cooldown", func(t *testing.T) {
+		now := time.Now()
+		breaker := newCircuitBreaker(1, time.Minute)
+		breaker.now = func() time.Time { return now }
+
+		breaker.record(true)
+		assert.False(t, breaker.allow())
+
+		now = now.Add(time.Minute)
+		assert.True(t, breaker.allow())
+		assert.Equal(t, circuitHalfOpen, breaker.currentState())
+		assert.False(t, breaker.allow())
+
+		breaker.record(false)
+		assert.Equal(t, circuitClosed, breaker.currentState())
+		assert.True(t, breaker.allow())
+	})
+}
+
+func TestCircuitBreakerPerHost(t *testing.T) {
+	t.Run("Test regional host failures leave the platform circuit breaker closed", func(t *testing.T) {
+		handler := http.NewServeMux()
+		handler.HandleFunc("/riot/account/v1/accounts/by-riot-id/xNibe/EUW", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		handler.HandleFunc("/lol/summoner/v4/summoners/test_id", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id": "test_id"}`))
+		})
+		server := httptest.NewServer(handler)
+		defer server.Close()
+		provider, err := NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			createEmptyCache(),
+			WithRegionalHosts(map[string]string{"europe": server.URL}),
+		)
+		assert.Nil(t, err)
+
+		for i := 0; i < circuitBreakerThreshold; i++ {
+			_, err = provider.FindSummonerByRegionAndName(context.Background(), "euw1", "xNibe#EUW")
+			assert.NotNil(t, err)
+		}
+		assert.Equal(t, map[string]string{"euw1": circuitClosed, "europe": circuitOpen}, provider.CircuitBreakerStates())
+
+		summoner, err := provider.FindSummonerByRegionAndId(context.Background(), "euw1", "test_id")
+		assert.Nil(t, err)
+		assert.Equal(t, "test_id", summoner.Id)
+	})
+}
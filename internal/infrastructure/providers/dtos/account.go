@@ -0,0 +1,8 @@
+package infrastructure
+
+// AccountDTO is the riot account behind a riot id (gameName#tagLine)
+type AccountDTO struct {
+	Puuid    string `json:"puuid"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+}
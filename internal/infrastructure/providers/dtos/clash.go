@@ -0,0 +1,43 @@
+package infrastructure
+
+// ClashPlayerDTO dto to map a clash registration of a summoner
+type ClashPlayerDTO struct {
+	SummonerId string `json:"summonerId"`
+	TeamId     string `json:"teamId"`
+	Position   string `json:"position"` //"TOP", "JUNGLE", "MIDDLE", "BOTTOM", "UTILITY", "FILL" or "UNSELECTED"
+	Role       string `json:"role"`     //"CAPTAIN" or "MEMBER"
+}
+
+type ClashTeamDTO struct {
+	Id           string           `json:"id"`
+	TournamentId int64            `json:"tournamentId"`
+	Name         string           `json:"name"`
+	IconId       int64            `json:"iconId"`
+	Tier         int              `json:"tier"`
+	Captain      string           `json:"captain"`
+	Abbreviation string           `json:"abbreviation"`
+	Players      []ClashPlayerDTO `json:"players"`
+}
+
+type ClashTournamentDTO struct {
+	Id               int64                     `json:"id"`
+	ThemeId          int64                     `json:"themeId"`
+	NameKey          string                    `json:"nameKey"`
+	NameKeySecondary string                    `json:"nameKeySecondary"`
+	Schedule         []ClashTournamentPhaseDTO `json:"schedule"`
+}
+
+type ClashTournamentPhaseDTO struct {
+	Id               int64 `json:"id"`
+	RegistrationTime int64 `json:"registrationTime"`
+	StartTime        int64 `json:"startTime"`
+	Cancelled        bool  `json:"cancelled"`
+}
+
+// ChampionMasteryDTO dto to map the mastery of a summoner with a champion
+type ChampionMasteryDTO struct {
+	ChampionId     int64 `json:"championId"`
+	ChampionLevel  int   `json:"championLevel"`
+	ChampionPoints int   `json:"championPoints"`
+	LastPlayTime   int64 `json:"lastPlayTime"`
+}
@@ -1,9 +1,30 @@
 package infrastructure
 
 type LeagueInfoDTO struct {
-	QueueType string `json:"queueType"`
-	Tier      string `json:"tier"` //"MASTER"
-	Rank      string `json:"rank"` //"I"
-	Wins      int `json:"wins"`
-	Losses    int `json:"losses"`
+	QueueType    string `json:"queueType"`
+	Tier         string `json:"tier"` //"MASTER"
+	Rank         string `json:"rank"` //"I"
+	LeaguePoints int    `json:"leaguePoints"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
+	SummonerId   string `json:"summonerId"`
+	SummonerName string `json:"summonerName"`
+}
+
+// LeagueListDTO dto to map the apex leagues, the entries share the tier of the league
+type LeagueListDTO struct {
+	LeagueId string          `json:"leagueId"`
+	Tier     string          `json:"tier"`
+	Name     string          `json:"name"`
+	Queue    string          `json:"queue"`
+	Entries  []LeagueItemDTO `json:"entries"`
+}
+
+type LeagueItemDTO struct {
+	SummonerId   string `json:"summonerId"`
+	SummonerName string `json:"summonerName"`
+	Rank         string `json:"rank"`
+	LeaguePoints int    `json:"leaguePoints"`
+	Wins         int    `json:"wins"`
+	Losses       int    `json:"losses"`
 }
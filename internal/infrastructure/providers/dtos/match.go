@@ -8,11 +8,20 @@ type MatchDTO struct {
 	GameQueueConfigId int64            `json:"gameQueueConfigId"`
 	MapId             int64            `json:"mapId"`
 	GameStartTime     int64            `json:"gameStartTime"`
+	GameLength        int64            `json:"gameLength"`
 	Participants      []ParticipantDTO `json:"participants"`
 }
 
+// FeaturedGamesDTO dto to map the featured games, their participants only have the summoner name
+type FeaturedGamesDTO struct {
+	GameList []MatchDTO `json:"gameList"`
+	// ClientRefreshInterval is the suggested seconds to wait before asking again
+	ClientRefreshInterval int64 `json:"clientRefreshInterval"`
+}
+
 type ParticipantDTO struct {
 	TeamId       int64  `json:"teamId"`
 	SummonerName string `json:"summonerName"`
 	SummonerId   string `json:"summonerId"`
+	ChampionId   int64  `json:"championId"`
 }
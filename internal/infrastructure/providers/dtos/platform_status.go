@@ -0,0 +1,36 @@
+package infrastructure
+
+// PlatformDataDTO dto to map the status of a rito platform
+type PlatformDataDTO struct {
+	Id           string      `json:"id"`
+	Name         string      `json:"name"`
+	Locales      []string    `json:"locales"`
+	Maintenances []StatusDTO `json:"maintenances"`
+	Incidents    []StatusDTO `json:"incidents"`
+}
+
+type StatusDTO struct {
+	Id                int64        `json:"id"`
+	MaintenanceStatus string       `json:"maintenance_status"` //"scheduled", "in_progress" or "complete"
+	IncidentSeverity  string       `json:"incident_severity"`  //"info", "warning" or "critical"
+	Titles            []ContentDTO `json:"titles"`
+	Updates           []UpdateDTO  `json:"updates"`
+	CreatedAt         string       `json:"created_at"`
+	ArchiveAt         string       `json:"archive_at"`
+	UpdatedAt         string       `json:"updated_at"`
+	Platforms         []string     `json:"platforms"`
+}
+
+type ContentDTO struct {
+	Locale  string `json:"locale"`
+	Content string `json:"content"`
+}
+
+type UpdateDTO struct {
+	Id           int64        `json:"id"`
+	Author       string       `json:"author"`
+	Publish      bool         `json:"publish"`
+	Translations []ContentDTO `json:"translations"`
+	CreatedAt    string       `json:"created_at"`
+	UpdatedAt    string       `json:"updated_at"`
+}
@@ -3,6 +3,7 @@
 // SummonerDTO dto to map answer from rito api
 type SummonerDTO struct {
 	Id    string `json:"id"`
+	Puuid string `json:"puuid"`
 	Name  string `json:"name"`
 	Level int    `json:"summonerLevel"`
 }
@@ -0,0 +1,165 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var tracer = otel.Tracer("github.com/emipochettino/loleros-api/internal/infrastructure/providers")
+
+// endpoint describes a rito api resource so the provider methods only have to declare it
+type endpoint struct {
+	// name identifies the endpoint in logs and metrics
+	name string
+	// path is a fmt template appended to the region host, it receives the call params
+	path string
+	// cacheKey is the prefix of the cache key, region and params are appended to it, empty skips the cache
+	cacheKey string
+	// ttl of the cached response, zero means the cache default expiration
+	ttl time.Duration
+	// notFound is returned when rito answers 404, when nil the zero value of the target is returned
+	notFound error
+	// regional endpoints are served by the regional host of the region instead of the region host
+	regional bool
+	// bypassCircuitBreaker sends the call even while the region breaker is open, without counting its result
+	bypassCircuitBreaker bool
+	// noRetry sends the call once, an exceeded rate limit is returned instead of waited for
+	noRetry bool
+}
+
+// call is a single execution of an endpoint
+type call struct {
+	// ctx carries the request id and logger of the request that needed the call
+	ctx      context.Context
+	endpoint endpoint
+	region   string
+	params   []string
+}
+
+// hostName returns the name of the host serving the call, the routing value of the region for a regional endpoint
+func (c call) hostName() string {
+	if c.endpoint.regional {
+		return regionalRouting[c.region]
+	}
+	return c.region
+}
+
+func (c call) url(host string) string {
+	params := make([]interface{}, len(c.params))
+	for i, param := range c.params {
+		// names come from the users, they must not change the path
+		params[i] = url.PathEscape(param)
+	}
+	return host + fmt.Sprintf(c.endpoint.path, params...)
+}
+
+func (c call) cacheKey() string {
+	return strings.Join(append([]string{c.endpoint.cacheKey, c.region}, c.params...), "_")
+}
+
+// execute resolves the call from the cache or requests it to rito decoding the body into target.
+// It returns the cached value when present, otherwise target once it was filled.
+// The call is traced in a span with a child span per request sent, so the retries show up.
+func (r ritoProvider) execute(c call, target interface{}) (interface{}, error) {
+	ctx, span := tracer.Start(c.ctx, "rito "+c.endpoint.name, trace.WithAttributes(
+		attribute.String("rito.endpoint", c.endpoint.name),
+		attribute.String("rito.region", c.region),
+	))
+	defer span.End()
+	c.ctx = ctx
+
+	host, err := r.hostOf(c)
+	if err != nil {
+		return nil, err
+	}
+	cached := c.endpoint.cacheKey != ""
+	if cached {
+		value, isCached := r.cache.Get(c.cacheKey())
+		r.metrics.ObserveCacheLookup(c.endpoint.cacheKey, isCached)
+		span.SetAttributes(attribute.Bool("cache.hit", isCached))
+		if isCached {
+			return value, nil
+		}
+	}
+	url := c.url(host)
+
+	attempt := 0
+	err = r.retryRequestIfLimitExceeded(c, func() error {
+		attempt++
+		return r.request(c, url, attempt, target)
+	})
+	if err != nil {
+		// a resource rito does not have, like the game of a summoner not playing, is an expected answer
+		if err != c.endpoint.notFound {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return nil, err
+	}
+
+	switch {
+	case !cached:
+	case c.endpoint.ttl > 0:
+		r.cache.Set(c.cacheKey(), target, c.endpoint.ttl)
+	default:
+		r.cache.SetDefault(c.cacheKey(), target)
+	}
+
+	return target, nil
+}
+
+// request sends the call to rito once
+func (r ritoProvider) request(c call, url string, attempt int, target interface{}) error {
+	ctx, span := tracer.Start(c.ctx, "HTTP GET", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.HTTPMethodKey.String(http.MethodGet),
+		semconv.HTTPURLKey.String(url),
+		attribute.Int("rito.attempt", attempt),
+	))
+	defer span.End()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := r.do(&Call{
+		Endpoint:             c.endpoint.name,
+		Region:               c.region,
+		Host:                 c.hostName(),
+		Request:              request,
+		BypassCircuitBreaker: c.endpoint.bypassCircuitBreaker,
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	defer response.Body.Close()
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(response.StatusCode))
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(response.Body).Decode(target)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return application.ErrRitoTokenRejected
+	case http.StatusNotFound:
+		if c.endpoint.notFound != nil {
+			return c.endpoint.notFound
+		}
+		return nil
+	case http.StatusTooManyRequests:
+		return fmt.Errorf(rateLimitExceededErrorMsg)
+	default:
+		return r.handleNotOkResponse(ctx, response, c.region, url)
+	}
+}
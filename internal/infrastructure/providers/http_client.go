@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ClientConfig tunes the http client used to reach rito
+type ClientConfig struct {
+	// Timeout of the whole request, retries excluded
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConnsPerHost   int
+	// CABundlePath is a PEM file added to the system pool, needed behind intercepting proxies
+	CABundlePath string
+	// ProxyURL is the outbound proxy, when empty the standard proxy env variables are used
+	ProxyURL string
+	// InsecureSkipVerify disables the certificate verification, only meant for local testing
+	InsecureSkipVerify bool
+}
+
+func DefaultClientConfig() ClientConfig {
+	return ClientConfig{
+		Timeout:               10 * time.Second,
+		DialTimeout:           5 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   20,
+	}
+}
+
+// ClientConfigFromEnv overrides the default config with the RITO_HTTP_* env variables
+func ClientConfigFromEnv() (ClientConfig, error) {
+	config := DefaultClientConfig()
+	durations := map[string]*time.Duration{
+		"RITO_HTTP_TIMEOUT":                 &config.Timeout,
+		"RITO_HTTP_DIAL_TIMEOUT":            &config.DialTimeout,
+		"RITO_HTTP_TLS_HANDSHAKE_TIMEOUT":   &config.TLSHandshakeTimeout,
+		"RITO_HTTP_RESPONSE_HEADER_TIMEOUT": &config.ResponseHeaderTimeout,
+		"RITO_HTTP_IDLE_CONN_TIMEOUT":       &config.IdleConnTimeout,
+	}
+	for name, duration := range durations {
+		value, exists := os.LookupEnv(name)
+		if !exists {
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return ClientConfig{}, fmt.Errorf("invalid %s: %s", name, err)
+		}
+		*duration = parsed
+	}
+
+	if value, exists := os.LookupEnv("RITO_HTTP_MAX_IDLE_CONNS_PER_HOST"); exists {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return ClientConfig{}, fmt.Errorf("invalid RITO_HTTP_MAX_IDLE_CONNS_PER_HOST: %s", err)
+		}
+		config.MaxIdleConnsPerHost = parsed
+	}
+	if value, exists := os.LookupEnv("RITO_HTTP_INSECURE_SKIP_VERIFY"); exists {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return ClientConfig{}, fmt.Errorf("invalid RITO_HTTP_INSECURE_SKIP_VERIFY: %s", err)
+		}
+		config.InsecureSkipVerify = parsed
+	}
+	config.CABundlePath = os.Getenv("RITO_HTTP_CA_BUNDLE")
+	config.ProxyURL = os.Getenv("RITO_HTTP_PROXY")
+
+	return config, nil
+}
+
+// NewHTTPClient builds the client from the config, certificates are verified unless told otherwise
+func NewHTTPClient(config ClientConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if len(config.CABundlePath) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		bundle, err := ioutil.ReadFile(config.CABundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca bundle: %s", err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("ca bundle %s has no valid certificates", config.CABundlePath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if len(config.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %s", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		// a custom dialer and tls config disable http2 unless it is asked explicitly
+		ForceAttemptHTTP2: true,
+	}
+
+	return &http.Client{Transport: transport, Timeout: config.Timeout}, nil
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHTTPClientVerifiesCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Run("Test default client rejects unknown certificates", func(t *testing.T) {
+		client, err := NewHTTPClient(DefaultClientConfig())
+		assert.Nil(t, err)
+		_, err = client.Get(server.URL)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Test client trusts the configured ca bundle", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ca_bundle")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		bundlePath := filepath.Join(dir, "ca.pem")
+		bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		assert.Nil(t, ioutil.WriteFile(bundlePath, bundle, 0600))
+
+		config := DefaultClientConfig()
+		config.CABundlePath = bundlePath
+		client, err := NewHTTPClient(config)
+		assert.Nil(t, err)
+		response, err := client.Get(server.URL)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("Test client with an invalid ca bundle returns an error", func(t *testing.T) {
+		config := DefaultClientConfig()
+		config.CABundlePath = "jsons/summoner_response.json"
+		_, err := NewHTTPClient(config)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"net/http"
+	"time"
+)
+
+// Call is the upstream request that goes through the middleware chain
+type Call struct {
+	// Endpoint name as declared in the provider
+	Endpoint string
+	Region   string
+	// Host names the rito host serving the call, the region for platform endpoints
+	// and the routing value, like europe, for regional ones
+	Host    string
+	Request *http.Request
+	// BypassCircuitBreaker is set for the calls that must reach rito while the region is considered down
+	BypassCircuitBreaker bool
+}
+
+// Handler sends a call to rito
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler to add behaviour around every upstream request
+type Middleware func(next Handler) Handler
+
+// MetricsObserver receives the outcome of every upstream request. Status is zero when the request failed.
+type MetricsObserver func(call *Call, status int, elapsed time.Duration, err error)
+
+// AuthMiddleware adds the rito token to every request
+func AuthMiddleware(token string) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			call.Request.Header.Set("X-Riot-Token", token)
+			return next(call)
+		}
+	}
+}
+
+// LoggingMiddleware logs every upstream request with its status and duration,
+// with the logger of the request context so the lines carry the request id
+func LoggingMiddleware() Middleware {
+	return MetricsMiddleware(func(call *Call, status int, elapsed time.Duration, err error) {
+		logger := logging.FromContext(call.Request.Context()).With(
+			"endpoint", call.Endpoint,
+			"region", call.Region,
+			"path", call.Request.URL.Path,
+			"elapsed", elapsed.String(),
+		)
+		if err != nil {
+			logger.Warn("rito request failed", "err", err)
+			return
+		}
+		logger.Info("rito request", "status", status)
+	})
+}
+
+// MetricsMiddleware reports every upstream request to the observer
+func MetricsMiddleware(observer MetricsObserver) Middleware {
+	return func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			start := time.Now()
+			response, err := next(call)
+			status := 0
+			if response != nil {
+				status = response.StatusCode
+			}
+			observer(call, status, time.Since(start), err)
+			return response, err
+		}
+	}
+}
+
+// chain builds the handler so the first middleware is the outermost one
+func chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -1,252 +1,492 @@
 package providers
 
 import (
-	"crypto/tls"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/avast/retry-go"
 	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
 	providers "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const rateLimitExceededErrorMsg = "rate limit exceeded"
 
+var (
+	summonerByNameEndpoint = endpoint{
+		name:     "summoner-v4.by-name",
+		path:     "/lol/summoner/v4/summoners/by-name/%s",
+		cacheKey: "summoner_by_name",
+		notFound: application.ErrSummonerNotFound,
+	}
+	summonerByPuuidEndpoint = endpoint{
+		name:     "summoner-v4.by-puuid",
+		path:     "/lol/summoner/v4/summoners/by-puuid/%s",
+		cacheKey: "summoner_by_puuid",
+		notFound: application.ErrSummonerNotFound,
+	}
+	accountByRiotIdEndpoint = endpoint{
+		name:     "account-v1.by-riot-id",
+		path:     "/riot/account/v1/accounts/by-riot-id/%s/%s",
+		cacheKey: "account_by_riot_id",
+		regional: true,
+		notFound: application.ErrSummonerNotFound,
+	}
+	summonerByIdEndpoint = endpoint{
+		name:     "summoner-v4.by-id",
+		path:     "/lol/summoner/v4/summoners/%s",
+		cacheKey: "summoner_by_id",
+		notFound: application.ErrSummonerNotFound,
+	}
+	leaguesBySummonerIdEndpoint = endpoint{
+		name:     "league-v4.entries-by-summoner",
+		path:     "/lol/league/v4/entries/by-summoner/%s",
+		cacheKey: "league_by_summoner_id",
+		notFound: fmt.Errorf("leagues not found"),
+	}
+	challengerLeagueEndpoint = endpoint{
+		name:     "league-v4.challenger",
+		path:     "/lol/league/v4/challengerleagues/by-queue/%s",
+		cacheKey: "challenger_league",
+		ttl:      10 * time.Minute,
+		notFound: fmt.Errorf("league not found"),
+	}
+	grandmasterLeagueEndpoint = endpoint{
+		name:     "league-v4.grandmaster",
+		path:     "/lol/league/v4/grandmasterleagues/by-queue/%s",
+		cacheKey: "grandmaster_league",
+		ttl:      10 * time.Minute,
+		notFound: fmt.Errorf("league not found"),
+	}
+	masterLeagueEndpoint = endpoint{
+		name:     "league-v4.master",
+		path:     "/lol/league/v4/masterleagues/by-queue/%s",
+		cacheKey: "master_league",
+		ttl:      10 * time.Minute,
+		notFound: fmt.Errorf("league not found"),
+	}
+	leagueEntriesEndpoint = endpoint{
+		name:     "league-v4.entries",
+		path:     "/lol/league/v4/entries/%s/%s/%s?page=%s",
+		cacheKey: "league_entries",
+		ttl:      10 * time.Minute,
+	}
+	championMasteriesBySummonerIdEndpoint = endpoint{
+		name:     "champion-mastery-v4.by-summoner",
+		path:     "/lol/champion-mastery/v4/champion-masteries/by-summoner/%s",
+		cacheKey: "champion_masteries_by_summoner_id",
+	}
+	clashPlayersBySummonerIdEndpoint = endpoint{
+		name:     "clash-v1.players-by-summoner",
+		path:     "/lol/clash/v1/players/by-summoner/%s",
+		cacheKey: "clash_players_by_summoner_id",
+		ttl:      time.Minute,
+	}
+	clashPlayersByPuuidEndpoint = endpoint{
+		name:     "clash-v1.players-by-puuid",
+		path:     "/lol/clash/v1/players/by-puuid/%s",
+		cacheKey: "clash_players_by_puuid",
+		ttl:      time.Minute,
+	}
+	clashTeamByIdEndpoint = endpoint{
+		name:     "clash-v1.team",
+		path:     "/lol/clash/v1/teams/%s",
+		cacheKey: "clash_team_by_id",
+		ttl:      time.Minute,
+		notFound: application.ErrClashTeamNotFound,
+	}
+	clashTournamentsEndpoint = endpoint{
+		name:     "clash-v1.tournaments",
+		path:     "/lol/clash/v1/tournaments",
+		cacheKey: "clash_tournaments",
+	}
+	featuredGamesEndpoint = endpoint{
+		name:     "spectator-v4.featured-games",
+		path:     "/lol/spectator/v4/featured-games",
+		cacheKey: "featured_games",
+		ttl:      2 * time.Minute,
+	}
+	platformStatusEndpoint = endpoint{
+		name:     "lol-status-v4.platform-data",
+		path:     "/lol/status/v4/platform-data",
+		cacheKey: "platform_status",
+		ttl:      time.Minute,
+		// the incidents explain why a region is down, the breaker would hide them exactly then
+		bypassCircuitBreaker: true,
+	}
+	activeGameBySummonerIdEndpoint = endpoint{
+		name:     "spectator-v4.active-game",
+		path:     "/lol/spectator/v4/active-games/by-summoner/%s",
+		cacheKey: "match_by_summoner_id",
+		// short lived so the end of the game is noticed
+		ttl:      30 * time.Second,
+		notFound: application.ErrMatchNotFound,
+	}
+	// tokenCheckEndpoint is the platform status without the cache, the cheapest request telling if the token is accepted
+	tokenCheckEndpoint = endpoint{
+		name:    "lol-status-v4.token-check",
+		path:    "/lol/status/v4/platform-data",
+		noRetry: true,
+	}
+)
+
+// cacheProbeKey is the entry written and read back to check the cache
+const cacheProbeKey = "health_probe"
+
+// regionalRouting is the regional host closest to each region. The account api is not served by sea,
+// so the oceania region goes to americas as rito recommends.
+var regionalRouting = map[string]string{
+	"br1":  "americas",
+	"la1":  "americas",
+	"la2":  "americas",
+	"na1":  "americas",
+	"oc1":  "americas",
+	"jp1":  "asia",
+	"kr":   "asia",
+	"eun1": "europe",
+	"euw1": "europe",
+	"ru":   "europe",
+	"tr1":  "europe",
+}
+
 type ritoProvider struct {
-	client http.Client
-	token  string
+	client *http.Client
 	host   map[string]string
-	cache  Cache
+	// regionalHost is keyed by routing value, americas, asia, europe...
+	regionalHost map[string]string
+	cache        Cache
+	breakers     map[string]*circuitBreaker
+	middlewares  []Middleware
+	do           Handler
+	metrics      Metrics
 }
 
-func (r ritoProvider) FindSummonerByRegionAndName(region string, name string) (*providers.SummonerDTO, error) {
-	if cached, isCached := r.cache.Get(fmt.Sprintf("summoner_by_name_%s_%s", region, name)); isCached {
-		return cached.(*providers.SummonerDTO), nil
+// FindSummonerByRegionAndName resolves a riot id (name#tag) through its account, the summoner v4 api
+// only knows the names without tag
+func (r ritoProvider) FindSummonerByRegionAndName(ctx context.Context, region string, name string) (*providers.SummonerDTO, error) {
+	if separator := strings.LastIndex(name, "#"); separator >= 0 {
+		return r.findSummonerByRiotId(ctx, region, name[:separator], name[separator+1:])
 	}
-	url := fmt.Sprintf("%s/lol/summoner/v4/summoners/by-name/%s", r.host[region], name)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	result, err := r.execute(call{ctx: ctx, endpoint: summonerByNameEndpoint, region: region, params: []string{name}}, &providers.SummonerDTO{})
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("X-Riot-Token", r.token)
-
-	var summonerDTO providers.SummonerDTO
-	err = r.retryRequestIfLimitExceeded(func() error {
-		response, err := r.client.Do(request)
-		if err != nil {
-			return err
-		}
-		defer response.Body.Close()
-
-		if response.StatusCode == http.StatusForbidden {
-			return fmt.Errorf("rito token can be expired")
-		}
-		if response.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("summoner not found")
-		}
-		if response.StatusCode == http.StatusTooManyRequests {
-			return fmt.Errorf(rateLimitExceededErrorMsg)
-		}
-		if response.StatusCode != http.StatusOK {
-			return r.handleNotOkResponse(response, url)
-		}
-
-		if err = json.NewDecoder(response.Body).Decode(&summonerDTO); err != nil {
-			return err
-		}
-
-		r.cache.SetDefault(fmt.Sprintf("summoner_by_name_%s_%s", region, name), &summonerDTO)
-
-		return nil
-	})
-
-	return &summonerDTO, err
+	return result.(*providers.SummonerDTO), nil
 }
 
-func (r ritoProvider) FindSummonerByRegionAndId(region string, id string) (*providers.SummonerDTO, error) {
-	if cached, isCached := r.cache.Get(fmt.Sprintf("summoner_by_id_%s_%s", region, id)); isCached {
-		return cached.(*providers.SummonerDTO), nil
-	}
-	url := fmt.Sprintf("%s/lol/summoner/v4/summoners/%s", r.host[region], id)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+func (r ritoProvider) findSummonerByRiotId(ctx context.Context, region string, gameName string, tagLine string) (*providers.SummonerDTO, error) {
+	result, err := r.execute(
+		call{ctx: ctx, endpoint: accountByRiotIdEndpoint, region: region, params: []string{gameName, tagLine}},
+		&providers.AccountDTO{},
+	)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("X-Riot-Token", r.token)
+	account := result.(*providers.AccountDTO)
 
-	var summonerDTO providers.SummonerDTO
-
-	err = r.retryRequestIfLimitExceeded(func() error {
-		response, err := r.client.Do(request)
-		if err != nil {
-			return err
-		}
-		defer response.Body.Close()
-
-		if response.StatusCode == http.StatusForbidden {
-			return fmt.Errorf("rito token can be expired")
-		}
-		if response.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("summoner not found")
-		}
-		if response.StatusCode == http.StatusTooManyRequests {
-			return fmt.Errorf(rateLimitExceededErrorMsg)
-		}
-		if response.StatusCode != http.StatusOK {
-			return r.handleNotOkResponse(response, url)
-		}
-
-		if err = json.NewDecoder(response.Body).Decode(&summonerDTO); err != nil {
-			return err
-		}
-
-		r.cache.SetDefault(fmt.Sprintf("summoner_by_id_%s_%s", region, id), &summonerDTO)
+	result, err = r.execute(
+		call{ctx: ctx, endpoint: summonerByPuuidEndpoint, region: region, params: []string{account.Puuid}},
+		&providers.SummonerDTO{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	// a copy, the cached summoner is shared
+	summoner := *result.(*providers.SummonerDTO)
+	summoner.Name = account.GameName + "#" + account.TagLine
+	return &summoner, nil
+}
 
-		return nil
-	})
+func (r ritoProvider) FindSummonerByRegionAndId(ctx context.Context, region string, id string) (*providers.SummonerDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: summonerByIdEndpoint, region: region, params: []string{id}}, &providers.SummonerDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*providers.SummonerDTO), nil
+}
 
-	return &summonerDTO, err
+func (r ritoProvider) FindLeaguesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.LeagueInfoDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: leaguesBySummonerIdEndpoint, region: region, params: []string{summonerId}}, &[]providers.LeagueInfoDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return *result.(*[]providers.LeagueInfoDTO), nil
 }
 
-func (r ritoProvider) FindLeaguesByRegionAndSummonerId(region string, summonerId string) ([]providers.LeagueInfoDTO, error) {
-	if cached, isCached := r.cache.Get(fmt.Sprintf("league_by_summoner_id_%s_%s", region, summonerId)); isCached {
-		return cached.([]providers.LeagueInfoDTO), nil
+func (r ritoProvider) FindMatchBySummonerId(ctx context.Context, region string, summonerId string) (*providers.MatchDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: activeGameBySummonerIdEndpoint, region: region, params: []string{summonerId}}, &providers.MatchDTO{})
+	if err != nil {
+		return nil, err
 	}
-	url := fmt.Sprintf("%s/lol/league/v4/entries/by-summoner/%s", r.host[region], summonerId)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	return result.(*providers.MatchDTO), nil
+}
 
+func (r ritoProvider) FindChampionMasteriesByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ChampionMasteryDTO, error) {
+	result, err := r.execute(
+		call{ctx: ctx, endpoint: championMasteriesBySummonerIdEndpoint, region: region, params: []string{summonerId}},
+		&[]providers.ChampionMasteryDTO{},
+	)
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("X-Riot-Token", r.token)
+	return *result.(*[]providers.ChampionMasteryDTO), nil
+}
 
-	var leagues []providers.LeagueInfoDTO
-	err = r.retryRequestIfLimitExceeded(func() error {
-		response, err := r.client.Do(request)
-		if err != nil {
-			return err
-		}
-		defer response.Body.Close()
+func (r ritoProvider) FindClashPlayersByRegionAndSummonerId(ctx context.Context, region string, summonerId string) ([]providers.ClashPlayerDTO, error) {
+	result, err := r.execute(
+		call{ctx: ctx, endpoint: clashPlayersBySummonerIdEndpoint, region: region, params: []string{summonerId}},
+		&[]providers.ClashPlayerDTO{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return *result.(*[]providers.ClashPlayerDTO), nil
+}
 
-		if response.StatusCode == http.StatusForbidden {
-			return fmt.Errorf("rito token can be expired")
-		}
-		if response.StatusCode == http.StatusNotFound {
-			return fmt.Errorf("leagues not found")
-		}
-		if response.StatusCode == http.StatusTooManyRequests {
-			return fmt.Errorf(rateLimitExceededErrorMsg)
-		}
-		if response.StatusCode != http.StatusOK {
-			return r.handleNotOkResponse(response, url)
-		}
+func (r ritoProvider) FindClashPlayersByRegionAndPuuid(ctx context.Context, region string, puuid string) ([]providers.ClashPlayerDTO, error) {
+	result, err := r.execute(
+		call{ctx: ctx, endpoint: clashPlayersByPuuidEndpoint, region: region, params: []string{puuid}},
+		&[]providers.ClashPlayerDTO{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return *result.(*[]providers.ClashPlayerDTO), nil
+}
 
-		if err = json.NewDecoder(response.Body).Decode(&leagues); err != nil {
-			return err
-		}
+func (r ritoProvider) FindClashTeamByRegionAndId(ctx context.Context, region string, teamId string) (*providers.ClashTeamDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: clashTeamByIdEndpoint, region: region, params: []string{teamId}}, &providers.ClashTeamDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*providers.ClashTeamDTO), nil
+}
 
-		r.cache.SetDefault(fmt.Sprintf("league_by_summoner_id_%s_%s", region, summonerId), leagues)
+func (r ritoProvider) FindClashTournamentsByRegion(ctx context.Context, region string) ([]providers.ClashTournamentDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: clashTournamentsEndpoint, region: region}, &[]providers.ClashTournamentDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return *result.(*[]providers.ClashTournamentDTO), nil
+}
 
-		return nil
-	})
+func (r ritoProvider) FindFeaturedMatchesByRegion(ctx context.Context, region string) (*providers.FeaturedGamesDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: featuredGamesEndpoint, region: region}, &providers.FeaturedGamesDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*providers.FeaturedGamesDTO), nil
+}
 
-	return leagues, err
+func (r ritoProvider) FindApexLeagueByRegionAndQueue(ctx context.Context, region string, tier string, queue string) (*providers.LeagueListDTO, error) {
+	apexEndpoints := map[string]endpoint{
+		"CHALLENGER":  challengerLeagueEndpoint,
+		"GRANDMASTER": grandmasterLeagueEndpoint,
+		"MASTER":      masterLeagueEndpoint,
+	}
+	apexEndpoint, exists := apexEndpoints[strings.ToUpper(tier)]
+	if !exists {
+		return nil, fmt.Errorf("%s is not an apex tier", tier)
+	}
+	result, err := r.execute(call{ctx: ctx, endpoint: apexEndpoint, region: region, params: []string{queue}}, &providers.LeagueListDTO{})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*providers.LeagueListDTO), nil
 }
 
-func (r ritoProvider) FindMatchBySummonerId(region string, summonerId string) (*providers.MatchDTO, error) {
-	if cached, isCached := r.cache.Get(fmt.Sprintf("match_by_summoner_id_%s_%s", region, summonerId)); isCached {
-		return cached.(*providers.MatchDTO), nil
+func (r ritoProvider) FindLeagueEntriesByRegionAndQueue(ctx context.Context, region string, queue string, tier string, division string, page int) ([]providers.LeagueInfoDTO, error) {
+	result, err := r.execute(
+		call{ctx: ctx, endpoint: leagueEntriesEndpoint, region: region, params: []string{queue, tier, division, strconv.Itoa(page)}},
+		&[]providers.LeagueInfoDTO{},
+	)
+	if err != nil {
+		return nil, err
 	}
-	url := fmt.Sprintf("%s/lol/spectator/v4/active-games/by-summoner/%s", r.host[region], summonerId)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	return *result.(*[]providers.LeagueInfoDTO), nil
+}
 
+func (r ritoProvider) FindPlatformStatusByRegion(ctx context.Context, region string) (*providers.PlatformDataDTO, error) {
+	result, err := r.execute(call{ctx: ctx, endpoint: platformStatusEndpoint, region: region}, &providers.PlatformDataDTO{})
 	if err != nil {
 		return nil, err
 	}
-	request.Header.Add("X-Riot-Token", r.token)
+	return result.(*providers.PlatformDataDTO), nil
+}
 
-	var matchDTO providers.MatchDTO
-	err = r.retryRequestIfLimitExceeded(func() error {
-		response, err := r.client.Do(request)
-		if err != nil {
-			return err
-		}
-		defer response.Body.Close()
+func (r ritoProvider) CircuitBreakerStates() map[string]string {
+	states := make(map[string]string, len(r.breakers))
+	for name, breaker := range r.breakers {
+		states[name] = breaker.currentState()
+	}
+	return states
+}
 
-		if response.StatusCode == 403 {
-			return fmt.Errorf("rito token can be expired")
-		}
-		if response.StatusCode == 404 {
-			return fmt.Errorf("match not found")
-		}
-		if response.StatusCode == http.StatusTooManyRequests {
-			return fmt.Errorf(rateLimitExceededErrorMsg)
-		}
-		if response.StatusCode != http.StatusOK {
-			return r.handleNotOkResponse(response, url)
-		}
+// CheckToken tries the regions in order until one answers, so a region down does not hide the token state
+func (r ritoProvider) CheckToken(ctx context.Context) error {
+	regions := make([]string, 0, len(r.host))
+	for region := range r.host {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
 
-		err = json.NewDecoder(response.Body).Decode(&matchDTO)
-		if err != nil {
+	err := fmt.Errorf("no region is configured")
+	for _, region := range regions {
+		_, err = r.execute(call{ctx: ctx, endpoint: tokenCheckEndpoint, region: region}, &providers.PlatformDataDTO{})
+		if err == nil || errors.Is(err, application.ErrRitoTokenRejected) {
 			return err
 		}
+	}
+	return err
+}
 
-		r.cache.SetDefault(fmt.Sprintf("match_by_summoner_id_%s_%s", region, summonerId), &matchDTO)
-
-		return nil
-	})
+func (r ritoProvider) CheckCache() error {
+	probe := strconv.FormatInt(time.Now().UnixNano(), 10)
+	r.cache.Set(cacheProbeKey, probe, time.Minute)
+	if value, found := r.cache.Get(cacheProbeKey); !found || value != probe {
+		return fmt.Errorf("the cache did not return the probe entry")
+	}
+	return nil
+}
 
-	return &matchDTO, err
+// hostOf returns the host serving the call, a regional endpoint is served by the routing value of the region
+func (r ritoProvider) hostOf(c call) (string, error) {
+	host, exists := r.host[c.region]
+	if !exists {
+		return "", application.UnknownRegionError{Region: c.region}
+	}
+	if !c.endpoint.regional {
+		return host, nil
+	}
+	host, exists = r.regionalHost[regionalRouting[c.region]]
+	if !exists {
+		return "", fmt.Errorf("no regional host is configured for region %s", c.region)
+	}
+	return host, nil
 }
 
-func (r ritoProvider) retryRequestIfLimitExceeded(requestFunction func() error) error {
+func (r ritoProvider) retryRequestIfLimitExceeded(c call, requestFunction func() error) error {
+	attempts := uint(5)
+	if c.endpoint.noRetry {
+		attempts = 1
+	}
 	return retry.Do(
 		requestFunction,
+		retry.Context(c.ctx),
+		retry.DelayType(func(n uint, err error, config *retry.Config) time.Duration {
+			delay := retry.DefaultDelayType(n, err, config)
+			r.metrics.ObserveRetry(c.endpoint.name, c.region, delay)
+			return delay
+		}),
 		retry.RetryIf(isLimitExceeded),
-		retry.Attempts(5),
+		retry.Attempts(attempts),
 		retry.Delay(1*time.Second),
 		retry.LastErrorOnly(true),
 	)
 }
 
-func (r ritoProvider) handleNotOkResponse(response *http.Response, url string) error {
+func (r ritoProvider) handleNotOkResponse(ctx context.Context, response *http.Response, region string, url string) error {
 	errorMsg, _ := ioutil.ReadAll(response.Body)
-	log.Printf("error when requested %s. Status %d - Response %s\n",
-		url,
-		response.StatusCode,
-		string(errorMsg),
+	logging.FromContext(ctx).Error("unexpected rito response",
+		"url", url,
+		"status", response.StatusCode,
+		"response", string(errorMsg),
 	)
-	return fmt.Errorf("uups, something went wrong")
+	return application.UpstreamError{Region: region, Status: response.StatusCode}
+}
+
+// Option customizes the provider on creation
+type Option func(provider *ritoProvider)
+
+// WithMiddlewares wraps every upstream request, the first middleware being the outermost
+func WithMiddlewares(middlewares ...Middleware) Option {
+	return func(provider *ritoProvider) {
+		provider.middlewares = append(provider.middlewares, middlewares...)
+	}
 }
 
-func NewRitoProvider(host map[string]string, token string, cache Cache) (application.RitoProvider, error) {
+// WithMetrics observes the cache lookups and the retries, the upstream requests are observed with MetricsMiddleware
+func WithMetrics(metrics Metrics) Option {
+	return func(provider *ritoProvider) {
+		provider.metrics = metrics
+	}
+}
+
+// WithRegionalHosts sets the hosts of the apis served by routing value instead of by region, like the account api
+func WithRegionalHosts(hosts map[string]string) Option {
+	return func(provider *ritoProvider) {
+		provider.regionalHost = hosts
+	}
+}
+
+// WithClient replaces the default http client built from DefaultClientConfig
+func WithClient(client *http.Client) Option {
+	return func(provider *ritoProvider) {
+		provider.client = client
+	}
+}
+
+// NewRitoProvider creates the provider. Each region and regional host gets its own circuit breaker.
+func NewRitoProvider(host map[string]string, token string, cache Cache, options ...Option) (application.RitoProvider, error) {
 	if len(token) == 0 {
 		return nil, fmt.Errorf("rito token should exist")
 	}
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	provider := ritoProvider{
+		host:         host,
+		regionalHost: map[string]string{},
+		cache:        cache,
+		metrics:      nopMetrics{},
+	}
+	for _, option := range options {
+		option(&provider)
+	}
+	provider.breakers = newCircuitBreakers(provider.host, provider.regionalHost)
+	if provider.client == nil {
+		client, err := NewHTTPClient(DefaultClientConfig())
+		if err != nil {
+			return nil, err
+		}
+		provider.client = client
 	}
 
-	//TODO receive this by parameter
-	//c := cache.New(30*time.Minute, 40*time.Minute)
+	client := provider.client
+	provider.do = chain(
+		func(call *Call) (*http.Response, error) {
+			return client.Do(call.Request)
+		},
+		append(provider.middlewares, circuitBreakerMiddleware(provider.breakers), AuthMiddleware(token))...,
+	)
 
-	return ritoProvider{
-		client: http.Client{Transport: tr},
-		token:  token,
-		host:   host,
-		cache:  cache,
-	}, nil
+	return provider, nil
 }
 
 func isLimitExceeded(err error) bool {
 	return err.Error() == rateLimitExceededErrorMsg
 }
 
+// Metrics observes the internals of the provider
+type Metrics interface {
+	// ObserveCacheLookup is called on every lookup with the cache key prefix of the endpoint
+	ObserveCacheLookup(prefix string, hit bool)
+	// ObserveRetry is called before waiting to retry a rate limited request
+	ObserveRetry(endpoint string, region string, wait time.Duration)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveCacheLookup(string, bool) {}
+
+func (nopMetrics) ObserveRetry(string, string, time.Duration) {}
+
 type Cache interface {
+	Set(k string, x interface{}, d time.Duration)
 	SetDefault(k string, x interface{})
 	Get(k string) (interface{}, bool)
 }
@@ -1,13 +1,17 @@
 package providers
 
 import (
+	"context"
 	"fmt"
+	"github.com/emipochettino/loleros-api/internal/application"
 	infrastructure "github.com/emipochettino/loleros-api/internal/infrastructure/providers/dtos"
+	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestNewRitoProvider(t *testing.T) {
@@ -43,11 +47,12 @@ func TestFindSummonerByRegionAndName(t *testing.T) {
 			createEmptyCache(),
 		)
 		assert.Nil(t, err)
-		result, err := provider.FindSummonerByRegionAndName("test_region", "test_name")
+		result, err := provider.FindSummonerByRegionAndName(context.Background(), "test_region", "test_name")
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.EqualValues(t, &infrastructure.SummonerDTO{
 			Id:    "flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX",
+			Puuid: "vaRYQXkiSj5D8mJUw8dFYdsktMLRYLABaF4SEnA9C69PX_yuJjsM9C1kdiCjvvVHnxpU9dUWvjqM8A",
 			Name:  "xNibe",
 			Level: 18,
 		}, result)
@@ -70,12 +75,12 @@ func TestFindSummonerByRegionAndNameWithErrors(t *testing.T) {
 			"Test get summoner by region and name with non existing name",
 			"jsons/errors/not_found_error.json",
 			http.StatusNotFound,
-			fmt.Errorf("summoner not found"),
+			application.ErrSummonerNotFound,
 		}, {
 			"Test get summoner by region and name when rito api does not response correctly",
 			"jsons/errors/internal_server_error.json",
 			http.StatusInternalServerError,
-			fmt.Errorf("uups, something went wrong"),
+			application.UpstreamError{Region: "test_region", Status: http.StatusInternalServerError},
 		},
 	}
 
@@ -95,7 +100,7 @@ func TestFindSummonerByRegionAndNameWithErrors(t *testing.T) {
 				createEmptyCache(),
 			)
 			assert.Nil(t, err)
-			_, err = provider.FindSummonerByRegionAndName("test_region", "test_name")
+			_, err = provider.FindSummonerByRegionAndName(context.Background(), "test_region", "test_name")
 			assert.NotNil(t, err)
 			assert.EqualValues(t, tt.expectedError, err)
 		})
@@ -127,11 +132,12 @@ func TestFindSummonerByRegionAndNameWithoutQuota(t *testing.T) {
 			createEmptyCache(),
 		)
 		assert.Nil(t, err)
-		result, err := provider.FindSummonerByRegionAndName("test_region", "test_name")
+		result, err := provider.FindSummonerByRegionAndName(context.Background(), "test_region", "test_name")
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.EqualValues(t, &infrastructure.SummonerDTO{
 			Id:    "flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX",
+			Puuid: "vaRYQXkiSj5D8mJUw8dFYdsktMLRYLABaF4SEnA9C69PX_yuJjsM9C1kdiCjvvVHnxpU9dUWvjqM8A",
 			Name:  "xNibe",
 			Level: 18,
 		}, result)
@@ -159,11 +165,12 @@ func TestFindSummonerByRegionAndId(t *testing.T) {
 		}
 		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 		assert.Nil(t, err)
-		result, err := provider.FindSummonerByRegionAndId("test_region", "test_id")
+		result, err := provider.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		assert.EqualValues(t, &infrastructure.SummonerDTO{
 			Id:    "flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX",
+			Puuid: "vaRYQXkiSj5D8mJUw8dFYdsktMLRYLABaF4SEnA9C69PX_yuJjsM9C1kdiCjvvVHnxpU9dUWvjqM8A",
 			Name:  "xNibe",
 			Level: 18,
 		}, result)
@@ -187,12 +194,12 @@ func TestFindSummonerByRegionAndIdWithErrors(t *testing.T) {
 			"Test get summoner by region and name with non existing name",
 			"jsons/errors/not_found_error.json",
 			http.StatusNotFound,
-			fmt.Errorf("summoner not found"),
+			application.ErrSummonerNotFound,
 		}, {
 			"Test get summoner by region and name when rito api does not response correctly",
 			"jsons/errors/internal_server_error.json",
 			http.StatusInternalServerError,
-			fmt.Errorf("uups, something went wrong"),
+			application.UpstreamError{Region: "test_region", Status: http.StatusInternalServerError},
 		},
 	}
 
@@ -217,7 +224,7 @@ func TestFindSummonerByRegionAndIdWithErrors(t *testing.T) {
 			}
 			provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 			assert.Nil(t, err)
-			_, err = provider.FindSummonerByRegionAndId("test_region", "test_id")
+			_, err = provider.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
 			assert.NotNil(t, err)
 			assert.EqualValues(t, tt.expectedError.Error(), err.Error())
 		})
@@ -244,7 +251,7 @@ func TestFindLeaguesByRegionAndSummonerId(t *testing.T) {
 		}
 		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 		assert.Nil(t, err)
-		result, err := provider.FindLeaguesByRegionAndSummonerId("test_region", "test_id")
+		result, err := provider.FindLeaguesByRegionAndSummonerId(context.Background(), "test_region", "test_id")
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		//todo assert values
@@ -272,7 +279,7 @@ func TestFindLeaguesByRegionAndSummonerIdWithErrors(t *testing.T) {
 			"Test find leagues by region and summoner id when rito api does not response correctly",
 			"jsons/errors/internal_server_error.json",
 			http.StatusInternalServerError,
-			fmt.Errorf("uups, something went wrong"),
+			application.UpstreamError{Region: "test_region", Status: http.StatusInternalServerError},
 		},
 	}
 
@@ -297,7 +304,7 @@ func TestFindLeaguesByRegionAndSummonerIdWithErrors(t *testing.T) {
 			}
 			provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 			assert.Nil(t, err)
-			_, err = provider.FindLeaguesByRegionAndSummonerId("test_region", "test_id")
+			_, err = provider.FindLeaguesByRegionAndSummonerId(context.Background(), "test_region", "test_id")
 			assert.NotNil(t, err)
 			assert.EqualValues(t, tt.expectedError, err)
 		})
@@ -324,7 +331,7 @@ func TestFindMatchBySummonerId(t *testing.T) {
 		}
 		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 		assert.Nil(t, err)
-		result, err := provider.FindMatchBySummonerId("test_region", "test_id")
+		result, err := provider.FindMatchBySummonerId(context.Background(), "test_region", "test_id")
 		assert.Nil(t, err)
 		assert.NotNil(t, result)
 		//todo assert values
@@ -352,7 +359,7 @@ func TestFindMatchBySummonerIdWithErrors(t *testing.T) {
 			"Test find match by region and summoner id with internal server error",
 			"jsons/errors/internal_server_error.json",
 			http.StatusInternalServerError,
-			fmt.Errorf("uups, something went wrong"),
+			application.UpstreamError{Region: "test_region", Status: http.StatusInternalServerError},
 		},
 	}
 
@@ -377,13 +384,263 @@ func TestFindMatchBySummonerIdWithErrors(t *testing.T) {
 			}
 			provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cacheMock)
 			assert.Nil(t, err)
-			_, err = provider.FindMatchBySummonerId("test_region", "test_id")
+			_, err = provider.FindMatchBySummonerId(context.Background(), "test_region", "test_id")
 			assert.NotNil(t, err)
 			assert.EqualValues(t, tt.expectedError, err)
 		})
 	}
 }
 
+func TestFindApexLeagueByRegionAndQueue(t *testing.T) {
+	t.Run("Test find the challenger league by region and queue successfully", func(t *testing.T) {
+		content, err := ioutil.ReadFile("jsons/challenger_league_response.json")
+		assert.Nil(t, err)
+		server := serverMock(
+			"/lol/league/v4/challengerleagues/by-queue/RANKED_SOLO_5x5",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(content)
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		result, err := provider.FindApexLeagueByRegionAndQueue(context.Background(), "test_region", "challenger", "RANKED_SOLO_5x5")
+		assert.Nil(t, err)
+		assert.Equal(t, "CHALLENGER", result.Tier)
+		assert.Len(t, result.Entries, 2)
+		assert.Equal(t, infrastructure.LeagueItemDTO{
+			SummonerId:   "Jvcm4m9X7Q5zR1wX3yOlkNqCz8l4ZkbN1fQ2m5sA8rkC2bE",
+			SummonerName: "Keria",
+			Rank:         "I",
+			LeaguePoints: 1340,
+			Wins:         290,
+			Losses:       220,
+		}, result.Entries[1])
+	})
+	t.Run("Test find the league of a tier that is not apex returns an error", func(t *testing.T) {
+		provider, err := NewRitoProvider(map[string]string{"test_region": "http://localhost"}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		result, err := provider.FindApexLeagueByRegionAndQueue(context.Background(), "test_region", "GOLD", "RANKED_SOLO_5x5")
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "GOLD is not an apex tier")
+	})
+}
+
+func TestFindLeagueEntriesByRegionAndQueue(t *testing.T) {
+	t.Run("Test find a page of league entries by region, queue, tier and division successfully", func(t *testing.T) {
+		content, err := ioutil.ReadFile("jsons/league_entries_response.json")
+		assert.Nil(t, err)
+		server := serverMock(
+			"/lol/league/v4/entries/RANKED_SOLO_5x5/DIAMOND/I",
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "2", r.URL.Query().Get("page"))
+				_, _ = w.Write(content)
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		result, err := provider.FindLeagueEntriesByRegionAndQueue(context.Background(), "test_region", "RANKED_SOLO_5x5", "DIAMOND", "I", 2)
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "xNibe", result[0].SummonerName)
+		assert.Equal(t, 75, result[0].LeaguePoints)
+	})
+}
+
+func TestFindSummonerByRegionAndNameEscapesTheName(t *testing.T) {
+	t.Run("Test the summoner name is escaped in the request path", func(t *testing.T) {
+		content, err := ioutil.ReadFile("jsons/summoner_response.json")
+		assert.Nil(t, err)
+		var requestedPath string
+		server := serverMock(
+			"/lol/summoner/v4/summoners/by-name/",
+			func(w http.ResponseWriter, r *http.Request) {
+				requestedPath = r.URL.EscapedPath()
+				_, _ = w.Write(content)
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		_, err = provider.FindSummonerByRegionAndName(context.Background(), "test_region", "Broken Blade/x?")
+		assert.Nil(t, err)
+		assert.Equal(t, "/lol/summoner/v4/summoners/by-name/Broken%20Blade%2Fx%3F", requestedPath)
+	})
+}
+
+func TestCheckToken(t *testing.T) {
+	t.Run("Test the token check skips the cache", func(t *testing.T) {
+		requests := 0
+		server := serverMock(
+			"/lol/status/v4/platform-data",
+			func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				_, _ = w.Write([]byte(`{"id": "EUW1"}`))
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", cache.New(time.Minute, time.Minute))
+		assert.Nil(t, err)
+		assert.Nil(t, provider.CheckToken(context.Background()))
+		assert.Nil(t, provider.CheckToken(context.Background()))
+		assert.Equal(t, 2, requests)
+	})
+	t.Run("Test the token check reports a rejected token", func(t *testing.T) {
+		server := serverMock(
+			"/lol/status/v4/platform-data",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "expired_token", createEmptyCache())
+		assert.Nil(t, err)
+		assert.Equal(t, application.ErrRitoTokenRejected, provider.CheckToken(context.Background()))
+	})
+	t.Run("Test the token check does not wait for an exceeded rate limit", func(t *testing.T) {
+		requests := 0
+		server := serverMock(
+			"/lol/status/v4/platform-data",
+			func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusTooManyRequests)
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(map[string]string{"test_region": server.URL}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		assert.NotNil(t, provider.CheckToken(context.Background()))
+		assert.Equal(t, 1, requests)
+	})
+}
+
+func TestFindSummonerByRegionAndRiotId(t *testing.T) {
+	t.Run("Test a riot id is resolved through its account", func(t *testing.T) {
+		summoner, err := ioutil.ReadFile("jsons/summoner_response.json")
+		assert.Nil(t, err)
+		account, err := ioutil.ReadFile("jsons/account_response.json")
+		assert.Nil(t, err)
+		var requestedPaths []string
+		handler := http.NewServeMux()
+		handler.HandleFunc("/riot/account/v1/accounts/by-riot-id/", func(w http.ResponseWriter, r *http.Request) {
+			requestedPaths = append(requestedPaths, r.URL.EscapedPath())
+			_, _ = w.Write(account)
+		})
+		handler.HandleFunc("/lol/summoner/v4/summoners/by-puuid/", func(w http.ResponseWriter, r *http.Request) {
+			requestedPaths = append(requestedPaths, r.URL.EscapedPath())
+			_, _ = w.Write(summoner)
+		})
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		provider, err := NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			createEmptyCache(),
+			WithRegionalHosts(map[string]string{"europe": server.URL}),
+		)
+		assert.Nil(t, err)
+		summonerDTO, err := provider.FindSummonerByRegionAndName(context.Background(), "euw1", "x Nibe#EUW")
+		assert.Nil(t, err)
+		assert.Equal(t, "flB50ZlPKdPOKSomx9Yep5FHrP-CGRdnkKHoH9nbhcLY_JxX", summonerDTO.Id)
+		assert.Equal(t, "xNibe#EUW", summonerDTO.Name)
+		assert.Equal(t, []string{
+			"/riot/account/v1/accounts/by-riot-id/x%20Nibe/EUW",
+			"/lol/summoner/v4/summoners/by-puuid/vaRYQXkiSj5D8mJUw8dFYdsktMLRYLABaF4SEnA9C69PX_yuJjsM9C1kdiCjvvVHnxpU9dUWvjqM8A",
+		}, requestedPaths)
+	})
+	t.Run("Test an unknown riot id is not found", func(t *testing.T) {
+		server := serverMock("/riot/account/v1/accounts/by-riot-id/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(
+			map[string]string{"euw1": server.URL},
+			"valid_token",
+			createEmptyCache(),
+			WithRegionalHosts(map[string]string{"europe": server.URL}),
+		)
+		assert.Nil(t, err)
+		_, err = provider.FindSummonerByRegionAndName(context.Background(), "euw1", "nobody#EUW")
+		assert.Equal(t, application.ErrSummonerNotFound, err)
+	})
+}
+
+func TestRegionalEndpoints(t *testing.T) {
+	regionalEndpoint := endpoint{name: "test.regional", path: "/riot/test/%s", regional: true}
+
+	t.Run("Test a regional endpoint is sent to the regional host of the region", func(t *testing.T) {
+		server := serverMock(
+			"/riot/test/test_id",
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"id": "test_id"}`))
+			})
+		defer server.Close()
+
+		provider, err := NewRitoProvider(
+			map[string]string{"euw1": "http://euw1.invalid"},
+			"valid_token",
+			createEmptyCache(),
+			WithRegionalHosts(map[string]string{"europe": server.URL}),
+		)
+		assert.Nil(t, err)
+		result, err := provider.(ritoProvider).execute(
+			call{ctx: context.Background(), endpoint: regionalEndpoint, region: "euw1", params: []string{"test_id"}},
+			&infrastructure.SummonerDTO{},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, "test_id", result.(*infrastructure.SummonerDTO).Id)
+	})
+	t.Run("Test a regional endpoint without regional host returns an error", func(t *testing.T) {
+		provider, err := NewRitoProvider(map[string]string{"euw1": "http://euw1.invalid"}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		_, err = provider.(ritoProvider).execute(
+			call{ctx: context.Background(), endpoint: regionalEndpoint, region: "euw1", params: []string{"test_id"}},
+			&infrastructure.SummonerDTO{},
+		)
+		assert.EqualError(t, err, "no regional host is configured for region euw1")
+	})
+}
+
+func TestFindSummonerByUnknownRegion(t *testing.T) {
+	t.Run("Test find summoner with a region without host returns an error", func(t *testing.T) {
+		provider, err := NewRitoProvider(map[string]string{"test_region": "http://localhost"}, "valid_token", createEmptyCache())
+		assert.Nil(t, err)
+		_, err = provider.FindSummonerByRegionAndName(context.Background(), "unknown_region", "test_name")
+		assert.EqualValues(t, application.UnknownRegionError{Region: "unknown_region"}, err)
+	})
+}
+
+func TestRitoProviderMiddlewares(t *testing.T) {
+	t.Run("Test middlewares wrap the upstream request", func(t *testing.T) {
+		content, err := ioutil.ReadFile("jsons/summoner_response.json")
+		assert.Nil(t, err)
+		server := serverMock(
+			"/lol/summoner/v4/summoners/test_id",
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "valid_token", r.Header.Get("X-Riot-Token"))
+				_, _ = w.Write(content)
+			})
+		defer server.Close()
+		var observed []string
+		observer := MetricsMiddleware(func(call *Call, status int, elapsed time.Duration, err error) {
+			observed = append(observed, fmt.Sprintf("%s %s %d", call.Endpoint, call.Region, status))
+		})
+		provider, err := NewRitoProvider(
+			map[string]string{"test_region": server.URL},
+			"valid_token",
+			createEmptyCache(),
+			WithMiddlewares(observer),
+		)
+		assert.Nil(t, err)
+		_, err = provider.FindSummonerByRegionAndId(context.Background(), "test_region", "test_id")
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"summoner-v4.by-id test_region 200"}, observed)
+	})
+}
+
 func serverMock(path string, handlerFunc func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
 	handler := http.NewServeMux()
 	handler.HandleFunc(path, handlerFunc)
@@ -395,6 +652,9 @@ func serverMock(path string, handlerFunc func(w http.ResponseWriter, r *http.Req
 
 func createEmptyCache() cacheMock {
 	return cacheMock{
+		setMocked: func(k string, x interface{}, d time.Duration) {
+			//do nothing
+		},
 		getMocked: func(k string) (interface{}, bool) {
 			return nil, false
 		},
@@ -405,10 +665,19 @@ func createEmptyCache() cacheMock {
 }
 
 type cacheMock struct {
+	setMocked        func(k string, x interface{}, d time.Duration)
 	setDefaultMocked func(k string, x interface{})
 	getMocked        func(k string) (interface{}, bool)
 }
 
+func (c cacheMock) Set(k string, x interface{}, d time.Duration) {
+	if c.setMocked == nil {
+		c.SetDefault(k, x)
+		return
+	}
+	c.setMocked(k, x, d)
+}
+
 func (c cacheMock) SetDefault(k string, x interface{}) {
 	c.setDefaultMocked(k, x)
 }
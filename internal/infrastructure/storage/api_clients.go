@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// apiClientsFile is the format of the api clients file, which can also be written by hand
+type apiClientsFile struct {
+	Clients []domain.APIClient `json:"clients"`
+}
+
+// FileAPIClientStore keeps the api clients in memory and rewrites the whole file on every change
+type FileAPIClientStore struct {
+	path    string
+	mu      sync.RWMutex
+	clients []domain.APIClient
+}
+
+// NewFileAPIClientStore loads the clients of the file, a missing file is created on the first issued key
+func NewFileAPIClientStore(path string) (*FileAPIClientStore, error) {
+	store := &FileAPIClientStore{path: path, clients: []domain.APIClient{}}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file apiClientsFile
+	if err = json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("invalid api clients file %s: %s", path, err)
+	}
+	for _, client := range file.Clients {
+		if client.Id == "" || client.Key == "" {
+			return nil, fmt.Errorf("invalid api clients file %s: every client needs an id and a key", path)
+		}
+		store.clients = append(store.clients, client)
+	}
+	return store, nil
+}
+
+func (s *FileAPIClientStore) SaveAPIClient(client domain.APIClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(append(s.clients, client))
+}
+
+func (s *FileAPIClientStore) DeleteAPIClient(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clients := make([]domain.APIClient, 0, len(s.clients))
+	for _, client := range s.clients {
+		if client.Id != id {
+			clients = append(clients, client)
+		}
+	}
+	if len(clients) == len(s.clients) {
+		return application.ErrAPIClientNotFound
+	}
+	return s.write(clients)
+}
+
+func (s *FileAPIClientStore) FindAPIClients() ([]domain.APIClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.APIClient{}, s.clients...), nil
+}
+
+// FindAPIClientByKey compares the digests of the keys in constant time and goes through every client,
+// so the time taken tells nothing about the keys
+func (s *FileAPIClientStore) FindAPIClientByKey(key string) (*domain.APIClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	digest := sha256.Sum256([]byte(key))
+	var found *domain.APIClient
+	for i := range s.clients {
+		clientDigest := sha256.Sum256([]byte(s.clients[i].Key))
+		if subtle.ConstantTimeCompare(digest[:], clientDigest[:]) == 1 {
+			client := s.clients[i]
+			found = &client
+		}
+	}
+	if found == nil {
+		return nil, application.ErrAPIClientNotFound
+	}
+	return found, nil
+}
+
+// write replaces the file through a rename so a crash never leaves it half written,
+// the clients in memory only change once the file is written
+func (s *FileAPIClientStore) write(clients []domain.APIClient) error {
+	content, err := json.MarshalIndent(apiClientsFile{Clients: clients}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	s.clients = clients
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileAPIClientStore(t *testing.T) {
+	t.Run("Test clients are saved to the file and found by key after a restart", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "storage")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "api_clients.json")
+		client := domain.APIClient{
+			Id:                "test_id",
+			Name:              "test_name",
+			Key:               "test_key",
+			RequestsPerMinute: 60,
+			CreatedAt:         time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC),
+		}
+
+		store, err := NewFileAPIClientStore(path)
+		assert.Nil(t, err)
+		assert.Nil(t, store.SaveAPIClient(client))
+
+		reloaded, err := NewFileAPIClientStore(path)
+		assert.Nil(t, err)
+		found, err := reloaded.FindAPIClientByKey("test_key")
+		assert.Nil(t, err)
+		assert.Equal(t, &client, found)
+		for _, key := range []string{"test_ke", "test_key_", "TEST_KEY", ""} {
+			_, err = reloaded.FindAPIClientByKey(key)
+			assert.Equal(t, application.ErrAPIClientNotFound, err, key)
+		}
+
+		assert.Nil(t, reloaded.DeleteAPIClient("test_id"))
+		assert.Equal(t, application.ErrAPIClientNotFound, reloaded.DeleteAPIClient("test_id"))
+		_, err = reloaded.FindAPIClientByKey("test_key")
+		assert.Equal(t, application.ErrAPIClientNotFound, err)
+	})
+
+	t.Run("Test a client without key is rejected", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "storage")
+		assert.Nil(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "api_clients.json")
+		assert.Nil(t, ioutil.WriteFile(path, []byte(`{"clients": [{"id": "test_id"}]}`), 0600))
+
+		_, err = NewFileAPIClientStore(path)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+)
+
+func (s *SQLiteStore) ArchiveMatch(game domain.ArchivedGame) error {
+	match, err := json.Marshal(game.Match)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT OR IGNORE INTO archived_games (region, game_id, match, archived_at) VALUES (?, ?, ?, ?)`,
+		game.Region,
+		game.GameId,
+		string(match),
+		game.ArchivedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		// the game was already archived by a previous lookup
+		return err
+	}
+
+	for _, summoner := range game.Summoners {
+		_, err = tx.Exec(
+			`INSERT OR IGNORE INTO archived_game_participants (region, game_id, summoner_id) VALUES (?, ?, ?)`,
+			game.Region,
+			game.GameId,
+			summoner.Id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) FindArchivedGames(region string, summonerId string, limit int) ([]domain.ArchivedGame, error) {
+	rows, err := s.db.Query(
+		`SELECT g.match, g.archived_at FROM archived_games g
+		JOIN archived_game_participants p ON p.region = g.region AND p.game_id = g.game_id
+		WHERE p.region = ? AND p.summoner_id = ?
+		ORDER BY g.archived_at DESC LIMIT ?`,
+		region,
+		summonerId,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	games := make([]domain.ArchivedGame, 0)
+	for rows.Next() {
+		game, err := scanArchivedGame(rows, region)
+		if err != nil {
+			return nil, err
+		}
+		games = append(games, *game)
+	}
+	return games, rows.Err()
+}
+
+func (s *SQLiteStore) FindArchivedGame(region string, gameId int64) (*domain.ArchivedGame, error) {
+	row := s.db.QueryRow(
+		`SELECT match, archived_at FROM archived_games WHERE region = ? AND game_id = ?`,
+		region,
+		gameId,
+	)
+	game, err := scanArchivedGame(row, region)
+	if err == sql.ErrNoRows {
+		return nil, application.ErrArchivedGameNotFound
+	}
+	return game, err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanArchivedGame(row scanner, region string) (*domain.ArchivedGame, error) {
+	var match string
+	game := domain.ArchivedGame{Region: region}
+	if err := row.Scan(&match, &game.ArchivedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(match), &game.Match); err != nil {
+		return nil, err
+	}
+	return &game, nil
+}
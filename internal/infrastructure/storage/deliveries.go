@@ -0,0 +1,52 @@
+package storage
+
+import "github.com/emipochettino/loleros-api/internal/domain"
+
+func (s *SQLiteStore) SaveDelivery(delivery domain.WebhookDelivery) error {
+	_, err := s.db.Exec(
+		`INSERT INTO webhook_deliveries (url, event_type, summoner_id, game_id, attempts, status_code, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.URL,
+		delivery.EventType,
+		delivery.SummonerId,
+		delivery.GameId,
+		delivery.Attempts,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.CreatedAt,
+	)
+	return err
+}
+
+func (s *SQLiteStore) FindDeliveries(limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := s.db.Query(
+		`SELECT id, url, event_type, summoner_id, game_id, attempts, status_code, error, created_at
+		FROM webhook_deliveries ORDER BY id DESC LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]domain.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err = rows.Scan(
+			&delivery.Id,
+			&delivery.URL,
+			&delivery.EventType,
+			&delivery.SummonerId,
+			&delivery.GameId,
+			&delivery.Attempts,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"database/sql"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+)
+
+func (s *SQLiteStore) CreateGroup(group domain.Group) (*domain.Group, error) {
+	result, err := s.db.Exec(`INSERT INTO groups (name, created_at) VALUES (?, ?)`, group.Name, group.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if group.Id, err = result.LastInsertId(); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (s *SQLiteStore) FindGroup(groupId int64) (*domain.Group, error) {
+	group := domain.Group{Id: groupId}
+	err := s.db.QueryRow(`SELECT name, created_at FROM groups WHERE id = ?`, groupId).Scan(&group.Name, &group.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, application.ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if group.Members, err = s.findGroupMembers(groupId); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (s *SQLiteStore) FindGroups() ([]domain.Group, error) {
+	rows, err := s.db.Query(`SELECT id, name, created_at FROM groups ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]domain.Group, 0)
+	for rows.Next() {
+		var group domain.Group
+		if err = rows.Scan(&group.Id, &group.Name, &group.CreatedAt); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	// the single connection has to be released before querying the members
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	for i := range groups {
+		if groups[i].Members, err = s.findGroupMembers(groups[i].Id); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+func (s *SQLiteStore) AddGroupMember(groupId int64, member domain.GroupMember) error {
+	_, err := s.db.Exec(
+		`INSERT INTO group_members (group_id, region, summoner_id, summoner_name) VALUES (?, ?, ?, ?)
+		ON CONFLICT (group_id, region, summoner_id) DO UPDATE SET summoner_name = excluded.summoner_name`,
+		groupId,
+		member.Region,
+		member.SummonerId,
+		member.SummonerName,
+	)
+	return err
+}
+
+func (s *SQLiteStore) RemoveGroupMember(groupId int64, region string, summonerId string) error {
+	_, err := s.db.Exec(
+		`DELETE FROM group_members WHERE group_id = ? AND region = ? AND summoner_id = ?`,
+		groupId,
+		region,
+		summonerId,
+	)
+	return err
+}
+
+func (s *SQLiteStore) findGroupMembers(groupId int64) ([]domain.GroupMember, error) {
+	rows, err := s.db.Query(
+		`SELECT region, summoner_id, summoner_name FROM group_members WHERE group_id = ? ORDER BY summoner_name`,
+		groupId,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]domain.GroupMember, 0)
+	for rows.Next() {
+		var member domain.GroupMember
+		if err = rows.Scan(&member.Region, &member.SummonerId, &member.SummonerName); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"database/sql"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"time"
+)
+
+func (s *SQLiteStore) SaveTrackedSummoner(summoner domain.TrackedSummoner) error {
+	_, err := s.db.Exec(
+		`INSERT INTO tracked_summoners (region, summoner_id, summoner_name, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (region, summoner_id) DO UPDATE SET summoner_name = excluded.summoner_name`,
+		summoner.Region,
+		summoner.SummonerId,
+		summoner.SummonerName,
+		summoner.CreatedAt,
+	)
+	return err
+}
+
+func (s *SQLiteStore) FindTrackedSummoners() ([]domain.TrackedSummoner, error) {
+	rows, err := s.db.Query(
+		`SELECT region, summoner_id, summoner_name, created_at FROM tracked_summoners ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tracked := make([]domain.TrackedSummoner, 0)
+	for rows.Next() {
+		var summoner domain.TrackedSummoner
+		err = rows.Scan(&summoner.Region, &summoner.SummonerId, &summoner.SummonerName, &summoner.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		tracked = append(tracked, summoner)
+	}
+	return tracked, rows.Err()
+}
+
+func (s *SQLiteStore) SaveLeagueSnapshot(snapshot domain.LeagueSnapshot) (bool, error) {
+	var tier, rank string
+	var leaguePoints, wins, losses int
+	err := s.db.QueryRow(
+		`SELECT tier, rank, league_points, wins, losses FROM league_snapshots
+		WHERE region = ? AND summoner_id = ? AND queue_type = ?
+		ORDER BY taken_at DESC LIMIT 1`,
+		snapshot.Region,
+		snapshot.SummonerId,
+		snapshot.QueueType,
+	).Scan(&tier, &rank, &leaguePoints, &wins, &losses)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if err == nil &&
+		tier == snapshot.Tier &&
+		rank == snapshot.Rank &&
+		leaguePoints == snapshot.LeaguePoints &&
+		wins == snapshot.Wins &&
+		losses == snapshot.Losses {
+		return false, nil
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO league_snapshots (region, summoner_id, queue_type, tier, rank, league_points, wins, losses, taken_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		snapshot.Region,
+		snapshot.SummonerId,
+		snapshot.QueueType,
+		snapshot.Tier,
+		snapshot.Rank,
+		snapshot.LeaguePoints,
+		snapshot.Wins,
+		snapshot.Losses,
+		snapshot.TakenAt,
+	)
+	return err == nil, err
+}
+
+func (s *SQLiteStore) FindLeagueSnapshots(region string, summonerId string, queueType string, since time.Time) ([]domain.LeagueSnapshot, error) {
+	rows, err := s.db.Query(
+		`SELECT tier, rank, league_points, wins, losses, taken_at FROM league_snapshots
+		WHERE region = ? AND summoner_id = ? AND queue_type = ? AND taken_at >= ?
+		ORDER BY taken_at`,
+		region,
+		summonerId,
+		queueType,
+		since.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]domain.LeagueSnapshot, 0)
+	for rows.Next() {
+		var tier, rank string
+		var leaguePoints, wins, losses int
+		var takenAt time.Time
+		if err = rows.Scan(&tier, &rank, &leaguePoints, &wins, &losses, &takenAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, domain.LeagueSnapshot{
+			Region:     region,
+			SummonerId: summonerId,
+			League:     domain.NewLeague(queueType, tier, rank, leaguePoints, wins, losses),
+			TakenAt:    takenAt,
+		})
+	}
+	return snapshots, rows.Err()
+}
+
+func (s *SQLiteStore) FindLatestLeagueSnapshot(region string, summonerId string, queueType string, at time.Time) (*domain.LeagueSnapshot, error) {
+	var tier, rank string
+	var leaguePoints, wins, losses int
+	var takenAt time.Time
+	err := s.db.QueryRow(
+		`SELECT tier, rank, league_points, wins, losses, taken_at FROM league_snapshots
+		WHERE region = ? AND summoner_id = ? AND queue_type = ? AND taken_at <= ?
+		ORDER BY taken_at DESC LIMIT 1`,
+		region,
+		summonerId,
+		queueType,
+		at.UTC(),
+	).Scan(&tier, &rank, &leaguePoints, &wins, &losses, &takenAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LeagueSnapshot{
+		Region:     region,
+		SummonerId: summonerId,
+		League:     domain.NewLeague(queueType, tier, rank, leaguePoints, wins, losses),
+		TakenAt:    takenAt,
+	}, nil
+}
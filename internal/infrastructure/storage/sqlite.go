@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are applied in order on every start, so each one has to be idempotent
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS watched_summoners (
+		region TEXT NOT NULL,
+		summoner_id TEXT NOT NULL,
+		summoner_name TEXT NOT NULL,
+		game_id INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (region, summoner_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		summoner_id TEXT NOT NULL,
+		game_id INTEGER NOT NULL,
+		attempts INTEGER NOT NULL,
+		status_code INTEGER NOT NULL,
+		error TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS tracked_summoners (
+		region TEXT NOT NULL,
+		summoner_id TEXT NOT NULL,
+		summoner_name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (region, summoner_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS league_snapshots (
+		region TEXT NOT NULL,
+		summoner_id TEXT NOT NULL,
+		queue_type TEXT NOT NULL,
+		tier TEXT NOT NULL,
+		rank TEXT NOT NULL,
+		league_points INTEGER NOT NULL,
+		wins INTEGER NOT NULL,
+		losses INTEGER NOT NULL,
+		taken_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS league_snapshots_by_summoner
+		ON league_snapshots (region, summoner_id, queue_type, taken_at)`,
+	`CREATE TABLE IF NOT EXISTS archived_games (
+		region TEXT NOT NULL,
+		game_id INTEGER NOT NULL,
+		match TEXT NOT NULL,
+		archived_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (region, game_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS archived_game_participants (
+		region TEXT NOT NULL,
+		game_id INTEGER NOT NULL,
+		summoner_id TEXT NOT NULL,
+		PRIMARY KEY (region, summoner_id, game_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS groups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS group_members (
+		group_id INTEGER NOT NULL REFERENCES groups (id),
+		region TEXT NOT NULL,
+		summoner_id TEXT NOT NULL,
+		summoner_name TEXT NOT NULL,
+		PRIMARY KEY (group_id, region, summoner_id)
+	)`,
+}
+
+// SQLiteStore implements the application repositories on top of a sqlite database
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens, or creates, the database at path and applies the migrations
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL", path))
+	if err != nil {
+		return nil, err
+	}
+	// sqlite allows a single writer, sharing one connection avoids locking errors
+	db.SetMaxOpenConns(1)
+
+	for _, migration := range migrations {
+		if _, err = db.Exec(migration); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("could not migrate the database: %s", err)
+		}
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+func (s *SQLiteStore) Ping() error {
+	return s.db.Ping()
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchlist(t *testing.T) {
+	t.Run("Test watched summoners are saved, updated and deleted", func(t *testing.T) {
+		store := newTestStore(t)
+		createdAt := time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC)
+
+		assert.Nil(t, store.SaveWatchedSummoner(domain.WatchedSummoner{
+			Region:       "euw1",
+			SummonerId:   "test_id",
+			SummonerName: "test_name",
+			CreatedAt:    createdAt,
+		}))
+		assert.Nil(t, store.UpdateWatchedGame("euw1", "test_id", 3620211084))
+		// watching again keeps the game in progress
+		assert.Nil(t, store.SaveWatchedSummoner(domain.WatchedSummoner{
+			Region:       "euw1",
+			SummonerId:   "test_id",
+			SummonerName: "new_name",
+			CreatedAt:    createdAt,
+		}))
+
+		watched, err := store.FindWatchedSummoners()
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.WatchedSummoner{{
+			Region:       "euw1",
+			SummonerId:   "test_id",
+			SummonerName: "new_name",
+			CreatedAt:    createdAt,
+			GameId:       3620211084,
+		}}, watched)
+
+		assert.Nil(t, store.DeleteWatchedSummoner("euw1", "test_id"))
+		watched, err = store.FindWatchedSummoners()
+		assert.Nil(t, err)
+		assert.Empty(t, watched)
+	})
+}
+
+func TestDeliveries(t *testing.T) {
+	t.Run("Test deliveries are returned latest first", func(t *testing.T) {
+		store := newTestStore(t)
+		for _, eventType := range []string{domain.LiveGameStarted, domain.LiveGameEnded} {
+			assert.Nil(t, store.SaveDelivery(domain.WebhookDelivery{
+				URL:        "http://localhost/webhook",
+				EventType:  eventType,
+				SummonerId: "test_id",
+				GameId:     1,
+				Attempts:   1,
+				StatusCode: 204,
+				CreatedAt:  time.Now().UTC(),
+			}))
+		}
+
+		deliveries, err := store.FindDeliveries(1)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, domain.LiveGameEnded, deliveries[0].EventType)
+	})
+}
+
+func TestLeagueSnapshots(t *testing.T) {
+	t.Run("Test league snapshots are only stored when the standing changes", func(t *testing.T) {
+		store := newTestStore(t)
+		start := time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC)
+		leagues := []domain.League{
+			domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 90, 10, 10),
+			domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 90, 10, 10),
+			domain.NewLeague(domain.SoloQueueType, "PLATINUM", "IV", 5, 11, 10),
+		}
+		var stored []bool
+		for i, league := range leagues {
+			saved, err := store.SaveLeagueSnapshot(domain.LeagueSnapshot{
+				Region:     "euw1",
+				SummonerId: "test_id",
+				League:     league,
+				TakenAt:    start.Add(time.Duration(i) * time.Hour),
+			})
+			assert.Nil(t, err)
+			stored = append(stored, saved)
+		}
+		assert.Equal(t, []bool{true, false, true}, stored)
+
+		snapshots, err := store.FindLeagueSnapshots("euw1", "test_id", domain.SoloQueueType, start.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.Len(t, snapshots, 1)
+		assert.Equal(t, "PLATINUM", snapshots[0].Tier)
+	})
+	t.Run("Test the latest league snapshot is the standing at the given time", func(t *testing.T) {
+		store := newTestStore(t)
+		start := time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC)
+		leagues := []domain.League{
+			domain.NewLeague(domain.SoloQueueType, "GOLD", "II", 50, 8, 10),
+			domain.NewLeague(domain.SoloQueueType, "GOLD", "I", 10, 10, 10),
+			domain.NewLeague(domain.SoloQueueType, "PLATINUM", "IV", 5, 11, 10),
+		}
+		for i, league := range leagues {
+			_, err := store.SaveLeagueSnapshot(domain.LeagueSnapshot{
+				Region:     "euw1",
+				SummonerId: "test_id",
+				League:     league,
+				TakenAt:    start.AddDate(0, 0, i*5),
+			})
+			assert.Nil(t, err)
+		}
+
+		latest, err := store.FindLatestLeagueSnapshot("euw1", "test_id", domain.SoloQueueType, start.AddDate(0, 0, 7))
+		assert.Nil(t, err)
+		assert.Equal(t, "GOLD", latest.Tier)
+		assert.Equal(t, "I", latest.Rank)
+
+		latest, err = store.FindLatestLeagueSnapshot("euw1", "test_id", domain.SoloQueueType, start.Add(-time.Minute))
+		assert.Nil(t, err)
+		assert.Nil(t, latest)
+	})
+}
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	dir, err := ioutil.TempDir("", "storage")
+	assert.Nil(t, err)
+	store, err := NewSQLiteStore(filepath.Join(dir, "test.db"))
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		_ = store.Close()
+		_ = os.RemoveAll(dir)
+	})
+	return store
+}
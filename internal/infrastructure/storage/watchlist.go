@@ -0,0 +1,53 @@
+package storage
+
+import "github.com/emipochettino/loleros-api/internal/domain"
+
+func (s *SQLiteStore) SaveWatchedSummoner(summoner domain.WatchedSummoner) error {
+	_, err := s.db.Exec(
+		`INSERT INTO watched_summoners (region, summoner_id, summoner_name, game_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (region, summoner_id) DO UPDATE SET summoner_name = excluded.summoner_name`,
+		summoner.Region,
+		summoner.SummonerId,
+		summoner.SummonerName,
+		summoner.GameId,
+		summoner.CreatedAt,
+	)
+	return err
+}
+
+func (s *SQLiteStore) UpdateWatchedGame(region string, summonerId string, gameId int64) error {
+	_, err := s.db.Exec(
+		`UPDATE watched_summoners SET game_id = ? WHERE region = ? AND summoner_id = ?`,
+		gameId,
+		region,
+		summonerId,
+	)
+	return err
+}
+
+func (s *SQLiteStore) DeleteWatchedSummoner(region string, summonerId string) error {
+	_, err := s.db.Exec(`DELETE FROM watched_summoners WHERE region = ? AND summoner_id = ?`, region, summonerId)
+	return err
+}
+
+func (s *SQLiteStore) FindWatchedSummoners() ([]domain.WatchedSummoner, error) {
+	rows, err := s.db.Query(
+		`SELECT region, summoner_id, summoner_name, game_id, created_at FROM watched_summoners ORDER BY created_at`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watched := make([]domain.WatchedSummoner, 0)
+	for rows.Next() {
+		var summoner domain.WatchedSummoner
+		err = rows.Scan(&summoner.Region, &summoner.SummonerId, &summoner.SummonerName, &summoner.GameId, &summoner.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		watched = append(watched, summoner)
+	}
+	return watched, rows.Err()
+}
@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// DataDragonURL serves the static data of the game, like the champion names
+const DataDragonURL = "https://ddragon.leagueoflegends.com"
+
+type championsDTO struct {
+	Data map[string]struct {
+		// Key is the champion id rito sends in the active game
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+// LoadChampionNamer reads the champion names of the latest data dragon version,
+// the champions released after loading it are named by DefaultChampionNamer
+func LoadChampionNamer(client *http.Client, baseURL string) (ChampionNamer, error) {
+	var versions []string
+	if err := getJSON(client, baseURL+"/api/versions.json", &versions); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("data dragon answered no version")
+	}
+
+	var champions championsDTO
+	if err := getJSON(client, fmt.Sprintf("%s/cdn/%s/data/en_US/champion.json", baseURL, versions[0]), &champions); err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(champions.Data))
+	for _, champion := range champions.Data {
+		id, err := strconv.ParseInt(champion.Key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key of champion %s: %s", champion.Name, err)
+		}
+		names[id] = champion.Name
+	}
+
+	return func(championId int64) string {
+		if name, exists := names[championId]; exists {
+			return name
+		}
+		return DefaultChampionNamer(championId)
+	}, nil
+}
+
+func getJSON(client *http.Client, url string, target interface{}) error {
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d", url, response.StatusCode)
+	}
+	return json.NewDecoder(response.Body).Decode(target)
+}
@@ -0,0 +1,40 @@
+package webhooks
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadChampionNamer(t *testing.T) {
+	t.Run("Test the champions of the latest version are named", func(t *testing.T) {
+		handler := http.NewServeMux()
+		handler.HandleFunc("/api/versions.json", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`["10.21.1", "10.20.1"]`))
+		})
+		handler.HandleFunc("/cdn/10.21.1/data/en_US/champion.json", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data": {
+				"Hecarim": {"id": "Hecarim", "key": "120", "name": "Hecarim"},
+				"MonkeyKing": {"id": "MonkeyKing", "key": "62", "name": "Wukong"}
+			}}`))
+		})
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		championName, err := LoadChampionNamer(server.Client(), server.URL)
+		assert.Nil(t, err)
+		assert.Equal(t, "Hecarim", championName(120))
+		assert.Equal(t, "Wukong", championName(62))
+		assert.Equal(t, "Champion 999", championName(999))
+	})
+	t.Run("Test data dragon failing returns an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := LoadChampionNamer(server.Client(), server.URL)
+		assert.EqualError(t, err, server.URL+"/api/versions.json answered 503")
+	})
+}
@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"strings"
+	"time"
+)
+
+const (
+	blueTeamId = 100
+	redTeamId  = 200
+
+	blueColor = 0x1e88e5
+	greyColor = 0x757575
+
+	soloQueueType = "RANKED_SOLO_5x5"
+)
+
+var queueNames = map[int64]string{
+	400: "normal draft",
+	420: "ranked solo/duo",
+	430: "normal blind",
+	440: "ranked flex",
+	450: "ARAM",
+}
+
+type discordPayload struct {
+	Content string         `json:"content"`
+	Embeds  []discordEmbed `json:"embeds,omitempty"`
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	Color     int            `json:"color"`
+	Timestamp string         `json:"timestamp"`
+	Fields    []discordField `json:"fields,omitempty"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// ChampionNamer resolves the champion name of an id, rito only sends the id in the active game
+type ChampionNamer func(championId int64) string
+
+// DefaultChampionNamer names the champions missing in data dragon, or all of them when it could not be loaded
+func DefaultChampionNamer(championId int64) string {
+	return fmt.Sprintf("Champion %d", championId)
+}
+
+// DiscordFormatter renders the events as discord webhook messages, with one embed field per team when the game starts
+func DiscordFormatter(championName ChampionNamer) Formatter {
+	if championName == nil {
+		championName = DefaultChampionNamer
+	}
+	return func(event domain.WatchEvent) ([]byte, error) {
+		payload := discordPayload{}
+		embed := discordEmbed{
+			Timestamp: event.OccurredAt.Format(time.RFC3339),
+		}
+
+		switch event.Type {
+		case domain.LiveGameStarted:
+			queue := "game"
+			if event.Match != nil {
+				if name, exists := queueNames[event.Match.QueueId]; exists {
+					queue = name + " game"
+				}
+			}
+			payload.Content = fmt.Sprintf("%s just started a %s", event.SummonerName, queue)
+			embed.Title = fmt.Sprintf("%s (%s)", event.SummonerName, strings.ToUpper(event.Region))
+			embed.Color = blueColor
+			if event.Match != nil {
+				embed.Fields = []discordField{
+					teamField("Blue team", blueTeamId, event.Match.Summoners, championName),
+					teamField("Red team", redTeamId, event.Match.Summoners, championName),
+				}
+			}
+		case domain.LiveGameEnded:
+			payload.Content = fmt.Sprintf("%s just finished their game", event.SummonerName)
+			embed.Title = fmt.Sprintf("%s (%s)", event.SummonerName, strings.ToUpper(event.Region))
+			embed.Color = greyColor
+		default:
+			return nil, fmt.Errorf("unknown event type %s", event.Type)
+		}
+
+		payload.Embeds = []discordEmbed{embed}
+		return json.Marshal(payload)
+	}
+}
+
+func teamField(name string, teamId int64, summoners []domain.Summoner, championName ChampionNamer) discordField {
+	var lines []string
+	for _, summoner := range summoners {
+		if summoner.TeamId != teamId {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"**%s** · %s · %s",
+			summoner.Name,
+			championName(summoner.ChampionId),
+			soloQueueStanding(summoner.Leagues),
+		))
+	}
+	if len(lines) == 0 {
+		lines = []string{"-"}
+	}
+	return discordField{Name: name, Value: strings.Join(lines, "\n"), Inline: true}
+}
+
+func soloQueueStanding(leagues []domain.League) string {
+	for _, league := range leagues {
+		if league.QueueType == soloQueueType {
+			return fmt.Sprintf("%s %s (%.0f%% WR)", league.Tier, league.Rank, league.WinRate*100)
+		}
+	}
+	return "Unranked"
+}
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDiscordFormatter(t *testing.T) {
+	t.Run("Test game started event is posted as a discord embed", func(t *testing.T) {
+		var received discordPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notifier := NewNotifier(server.Client(), &deliveryRepositoryMock{}, Target{
+			URL: server.URL,
+			Formatter: DiscordFormatter(func(championId int64) string {
+				return map[int64]string{120: "Hecarim", 22: "Ashe"}[championId]
+			}),
+		})
+		notifier.Notify(domain.WatchEvent{
+			Type:         domain.LiveGameStarted,
+			Region:       "euw1",
+			SummonerName: "xNibe",
+			OccurredAt:   time.Date(2020, 10, 16, 22, 0, 0, 0, time.UTC),
+			Match: &domain.Match{
+				QueueId: 420,
+				Summoners: []domain.Summoner{
+					domain.NewSummoner("1", "xNibe", 18, 100, 120, []domain.League{
+						domain.NewLeague("RANKED_SOLO_5x5", "DIAMOND", "II", 81, 3, 1),
+					}),
+					domain.NewSummoner("2", "Boy Wonder", 30, 200, 22, nil),
+				},
+			},
+		})
+		notifier.Wait()
+
+		assert.Equal(t, "xNibe just started a ranked solo/duo game", received.Content)
+		assert.Equal(t, []discordEmbed{{
+			Title:     "xNibe (EUW1)",
+			Color:     blueColor,
+			Timestamp: "2020-10-16T22:00:00Z",
+			Fields: []discordField{
+				{Name: "Blue team", Value: "**xNibe** · Hecarim · DIAMOND II (75% WR)", Inline: true},
+				{Name: "Red team", Value: "**Boy Wonder** · Ashe · Unranked", Inline: true},
+			},
+		}}, received.Embeds)
+	})
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/avast/retry-go"
+	"github.com/emipochettino/loleros-api/internal/application"
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Loleros-Signature"
+	TimestampHeader = "X-Loleros-Timestamp"
+)
+
+// Formatter renders the event into the body posted to the webhook
+type Formatter func(event domain.WatchEvent) ([]byte, error)
+
+// JSONFormatter posts the event as is
+func JSONFormatter(event domain.WatchEvent) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+// Target is a webhook the events are posted to
+type Target struct {
+	URL string
+	// Secret signs the body, no signature is sent when empty
+	Secret    string
+	Formatter Formatter
+}
+
+// Notifier posts every event to each target in background, retrying failed deliveries and logging every one of them
+type Notifier struct {
+	client     *http.Client
+	targets    []Target
+	deliveries application.DeliveryRepository
+	attempts   uint
+	delay      time.Duration
+	wg         *sync.WaitGroup
+}
+
+func NewNotifier(client *http.Client, deliveries application.DeliveryRepository, targets ...Target) *Notifier {
+	for i := range targets {
+		if targets[i].Formatter == nil {
+			targets[i].Formatter = JSONFormatter
+		}
+	}
+	return &Notifier{
+		client:     client,
+		targets:    targets,
+		deliveries: deliveries,
+		attempts:   3,
+		delay:      time.Second,
+		wg:         &sync.WaitGroup{},
+	}
+}
+
+func (n *Notifier) Notify(event domain.WatchEvent) {
+	for _, target := range n.targets {
+		n.wg.Add(1)
+		go func(target Target) {
+			defer n.wg.Done()
+			n.deliver(target, event)
+		}(target)
+	}
+}
+
+// Wait blocks until the deliveries in progress are done
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+func (n *Notifier) deliver(target Target, event domain.WatchEvent) {
+	delivery := domain.WebhookDelivery{
+		URL:        target.URL,
+		EventType:  event.Type,
+		SummonerId: event.SummonerId,
+		GameId:     event.GameId,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	body, err := target.Formatter(event)
+	if err == nil {
+		err = retry.Do(
+			func() error {
+				delivery.Attempts++
+				statusCode, postErr := n.post(target, body)
+				delivery.StatusCode = statusCode
+				return postErr
+			},
+			retry.RetryIf(func(err error) bool {
+				return delivery.StatusCode == 0 ||
+					delivery.StatusCode == http.StatusTooManyRequests ||
+					delivery.StatusCode >= http.StatusInternalServerError
+			}),
+			retry.Attempts(n.attempts),
+			retry.Delay(n.delay),
+			retry.LastErrorOnly(true),
+		)
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		logging.Default().Warn("could not deliver the event", "event", event.Type, "url", target.URL, "err", err)
+	}
+
+	if err = n.deliveries.SaveDelivery(delivery); err != nil {
+		logging.Default().Error("could not save the delivery", "url", target.URL, "err", err)
+	}
+}
+
+func (n *Notifier) post(target Target, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if len(target.Secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request.Header.Set(TimestampHeader, timestamp)
+		request.Header.Set(SignatureHeader, Sign(target.Secret, timestamp, body))
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return response.StatusCode, fmt.Errorf("webhook answered %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// Sign returns the signature header value, a hmac sha256 of "<timestamp>.<body>" keyed with the secret
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package webhooks
+
+import (
+	"github.com/emipochettino/loleros-api/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifierDeliversSignedEvents(t *testing.T) {
+	t.Run("Test notifier retries failed deliveries and logs them", func(t *testing.T) {
+		requestNumber := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestNumber++
+			body, _ := ioutil.ReadAll(r.Body)
+			expected := Sign("test_secret", r.Header.Get(TimestampHeader), body)
+			assert.Equal(t, expected, r.Header.Get(SignatureHeader))
+			if requestNumber == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		deliveries := &deliveryRepositoryMock{}
+		notifier := NewNotifier(server.Client(), deliveries, Target{URL: server.URL, Secret: "test_secret"})
+		notifier.delay = time.Millisecond
+		notifier.Notify(domain.WatchEvent{Type: domain.LiveGameStarted, SummonerId: "test_id", GameId: 1})
+		notifier.Wait()
+
+		assert.Len(t, deliveries.saved, 1)
+		assert.Equal(t, 2, deliveries.saved[0].Attempts)
+		assert.Equal(t, http.StatusNoContent, deliveries.saved[0].StatusCode)
+		assert.Empty(t, deliveries.saved[0].Error)
+	})
+}
+
+type deliveryRepositoryMock struct {
+	mu    sync.Mutex
+	saved []domain.WebhookDelivery
+}
+
+func (d *deliveryRepositoryMock) SaveDelivery(delivery domain.WebhookDelivery) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.saved = append(d.saved, delivery)
+	return nil
+}
+
+func (d *deliveryRepositoryMock) FindDeliveries(limit int) ([]domain.WebhookDelivery, error) {
+	return d.saved, nil
+}
@@ -1,26 +1,214 @@
 package main
 
 import (
+	"context"
 	"github.com/emipochettino/loleros-api/internal/application"
 	infraAdapters "github.com/emipochettino/loleros-api/internal/infrastructure/adpaters"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/logging"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/metrics"
 	"github.com/emipochettino/loleros-api/internal/infrastructure/providers"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/storage"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/tracing"
+	"github.com/emipochettino/loleros-api/internal/infrastructure/webhooks"
 	"github.com/patrickmn/go-cache"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 )
 
 func main() {
+	logger, err := logging.FromEnv()
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read the log config. %s", err)
+	}
+	logging.SetDefault(logger)
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		log.Fatalf("Something went wrong trying to set up the tracing. %s", err)
+	}
+
+	metricsRegistry := metrics.NewRegistry()
 	ritoToken := os.Getenv("RITO_TOKEN")
 	c := cache.New(30*time.Minute, 40*time.Minute)
-	ritoProvider, err := providers.NewRitoProvider(application.GetRitoHosts(), ritoToken, c)
+	hostsConfig, err := application.LoadHostsConfig()
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read the rito hosts config. %s", err)
+	}
+	clientConfig, err := providers.ClientConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read the rito client config. %s", err)
+	}
+	client, err := providers.NewHTTPClient(clientConfig)
+	if err != nil {
+		log.Fatalf("Something went wrong trying to create the rito client. %s", err)
+	}
+	if cassetteMode := os.Getenv("RITO_CASSETTE_MODE"); len(cassetteMode) > 0 {
+		client, err = providers.NewCassetteClient(client, cassetteMode, os.Getenv("RITO_CASSETTE"))
+		if err != nil {
+			log.Fatalf("Something went wrong trying to load the rito cassette. %s", err)
+		}
+		if cassetteMode == providers.CassetteModeReplay && len(ritoToken) == 0 {
+			ritoToken = "replay"
+		}
+	}
+	ritoProvider, err := providers.NewRitoProvider(
+		hostsConfig.Platforms,
+		ritoToken,
+		c,
+		providers.WithClient(client),
+		providers.WithRegionalHosts(hostsConfig.Regionals),
+		providers.WithMiddlewares(providers.LoggingMiddleware(), providers.MetricsMiddleware(metricsRegistry.ObserveUpstream)),
+		providers.WithMetrics(metricsRegistry),
+	)
 	if err != nil {
 		log.Fatalf("Something went wrong trying to create rito provider. %s", err)
 	}
-	matchService := application.NewMatchService(ritoProvider)
+	store, err := storage.NewSQLiteStore(getEnvOrDefault("STORAGE_PATH", "loleros.db"))
+	if err != nil {
+		log.Fatalf("Something went wrong trying to open the storage. %s", err)
+	}
+	var webhookTargets []webhooks.Target
+	for _, url := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if len(strings.TrimSpace(url)) > 0 {
+			webhookTargets = append(webhookTargets, webhooks.Target{
+				URL:    strings.TrimSpace(url),
+				Secret: os.Getenv("WEBHOOK_SECRET"),
+			})
+		}
+	}
+	if discordURL := os.Getenv("DISCORD_WEBHOOK_URL"); len(discordURL) > 0 {
+		championNamer, err := webhooks.LoadChampionNamer(
+			&http.Client{Timeout: 10 * time.Second},
+			getEnvOrDefault("DATA_DRAGON_URL", webhooks.DataDragonURL),
+		)
+		if err != nil {
+			logger.Warn("could not load the champion names, the discord messages show the champion ids", "err", err)
+			championNamer = webhooks.DefaultChampionNamer
+		}
+		webhookTargets = append(webhookTargets, webhooks.Target{
+			URL:       discordURL,
+			Formatter: webhooks.DiscordFormatter(championNamer),
+		})
+	}
+	notifier := webhooks.NewNotifier(&http.Client{Timeout: 10 * time.Second}, store, webhookTargets...)
+	budgetPerMinute, err := strconv.Atoi(getEnvOrDefault("WATCHLIST_BUDGET_PER_MINUTE", "30"))
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read WATCHLIST_BUDGET_PER_MINUTE. %s", err)
+	}
+	watchlistScheduler := application.NewWatchlistScheduler(ritoProvider, store, notifier, budgetPerMinute)
+	watchlistScheduler.Start()
+
+	snapshotInterval, err := time.ParseDuration(getEnvOrDefault("RANK_SNAPSHOT_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read RANK_SNAPSHOT_INTERVAL. %s", err)
+	}
+	rankHistoryScheduler := application.NewRankHistoryScheduler(ritoProvider, store, snapshotInterval)
+	rankHistoryScheduler.Start()
+
+	groupService := application.NewGroupService(ritoProvider, store, store)
+	leaderboardInterval, err := time.ParseDuration(getEnvOrDefault("LEADERBOARD_REFRESH_INTERVAL", "10m"))
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read LEADERBOARD_REFRESH_INTERVAL. %s", err)
+	}
+	leaderboardScheduler := application.NewLeaderboardScheduler(groupService, leaderboardInterval)
+	leaderboardScheduler.Start()
+
+	matchService := application.NewMatchService(ritoProvider, store, metricsRegistry)
+	liveGameService := application.NewLiveGameService(ritoProvider, 30*time.Second)
 	ritoHandler := infraAdapters.RitoHandler{
-		MatchService: matchService,
+		MatchService:       matchService,
+		HealthService:      application.NewHealthService(ritoProvider, store),
+		LiveGameService:    liveGameService,
+		WatchlistService:   application.NewWatchlistService(ritoProvider, store, store),
+		RankHistoryService: application.NewRankHistoryService(ritoProvider, store),
+		GroupService:       groupService,
+		LadderService:      application.NewLadderService(ritoProvider),
+		StatusService:      application.NewStatusService(ritoProvider),
+		ClashService:       application.NewClashService(ritoProvider),
+		SummonerService:    application.NewSummonerService(ritoProvider),
+		Regions:            hostsConfig.Regions(),
+		AdminKey:           os.Getenv("ADMIN_API_KEY"),
+		Logger:             logger,
+		Metrics:            metricsRegistry,
+	}
+	if apiClientsPath := os.Getenv("API_CLIENTS_FILE"); len(apiClientsPath) > 0 {
+		apiClients, err := storage.NewFileAPIClientStore(apiClientsPath)
+		if err != nil {
+			log.Fatalf("Something went wrong trying to load the api clients. %s", err)
+		}
+		requestsPerMinute, err := strconv.Atoi(getEnvOrDefault("API_CLIENT_REQUESTS_PER_MINUTE", "60"))
+		if err != nil {
+			log.Fatalf("Something went wrong trying to read API_CLIENT_REQUESTS_PER_MINUTE. %s", err)
+		}
+		ritoHandler.APIClientService = application.NewAPIClientService(apiClients, requestsPerMinute)
+	} else {
+		logger.Warn("API_CLIENTS_FILE is not set, the api is open to everyone")
+	}
+
+	serverConfig, err := infraAdapters.ServerConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Something went wrong trying to read the server config. %s", err)
+	}
+	listener, err := net.Listen("tcp", serverConfig.Addr)
+	if err != nil {
+		log.Fatalf("Something went wrong trying to listen on %s. %s", serverConfig.Addr, err)
+	}
+	server := infraAdapters.NewServer(infraAdapters.NewRouter(ritoHandler), serverConfig)
+	// closing the subscriptions ends the match streams, otherwise they would hold the shutdown until the timeout
+	server.RegisterOnShutdown(liveGameService.Stop)
+
+	ctx, stop := signalContext(syscall.SIGINT, syscall.SIGTERM)
+	exitCode := 0
+	if err = infraAdapters.Serve(ctx, server, listener, serverConfig.ShutdownTimeout); err != nil {
+		logger.Error("the server stopped unexpectedly", "err", err)
+		exitCode = 1
+	}
+	stop()
+
+	// the schedulers finish the run in progress, whose webhooks are waited for before closing the store they log to
+	leaderboardScheduler.Stop()
+	rankHistoryScheduler.Stop()
+	watchlistScheduler.Stop()
+	notifier.Wait()
+	if err = shutdownTracing(context.Background()); err != nil {
+		logger.Error("could not flush the traces", "err", err)
+		exitCode = 1
 	}
+	// closing the store checkpoints its write ahead log into the database file
+	if err = store.Close(); err != nil {
+		logger.Error("could not close the storage", "err", err)
+		exitCode = 1
+	}
+	logger.Info("stopped")
+	os.Exit(exitCode)
+}
 
-	_ = infraAdapters.NewRouter(ritoHandler).Run()
+// signalContext is done once one of the signals is received, a second one kills the process right away
+func signalContext(signals ...os.Signal) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	go func() {
+		select {
+		case sig := <-received:
+			logging.Default().Info("signal received", "signal", sig.String())
+		case <-ctx.Done():
+		}
+		signal.Stop(received)
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+func getEnvOrDefault(name string, defaultValue string) string {
+	if value, exists := os.LookupEnv(name); exists {
+		return value
+	}
+	return defaultValue
 }